@@ -513,7 +513,7 @@ policy
   .command('explain')
   .description('Explain which rule would apply for a given context')
   .argument('<file>', 'Path to policy file')
-  .option('-t, --type <type>', 'Subject type (human, org, agent)')
+  .option('-t, --type <type>', 'Subject type (human, org, agent, service, unknown)')
   .option('-l, --labels <labels>', 'Subject labels (comma-separated)')
   .option('-i, --id <id>', 'Subject ID')
   .option(
@@ -331,7 +331,7 @@ describe('complex policy validation', () => {
   });
 
   it('should validate all valid subject types', () => {
-    const types = ['human', 'org', 'agent'];
+    const types = ['human', 'org', 'agent', 'service', 'unknown'];
 
     for (const type of types) {
       const policy = {
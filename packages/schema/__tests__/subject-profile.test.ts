@@ -22,6 +22,14 @@ describe('SubjectTypeSchema', () => {
     expect(SubjectTypeSchema.safeParse('agent').success).toBe(true);
   });
 
+  it('accepts "service"', () => {
+    expect(SubjectTypeSchema.safeParse('service').success).toBe(true);
+  });
+
+  it('accepts "unknown"', () => {
+    expect(SubjectTypeSchema.safeParse('unknown').success).toBe(true);
+  });
+
   it('rejects invalid type', () => {
     expect(SubjectTypeSchema.safeParse('bot').success).toBe(false);
     expect(SubjectTypeSchema.safeParse('').success).toBe(false);
@@ -28,8 +28,10 @@ export type SubjectId = string;
  * - "human": Individual person
  * - "org": Organization or legal entity
  * - "agent": Autonomous software agent (AI, bot, crawler)
+ * - "service": Non-human, non-agent machine caller (internal service, scheduler)
+ * - "unknown": Caller whose type could not be determined
  */
-export type SubjectType = 'human' | 'org' | 'agent';
+export type SubjectType = 'human' | 'org' | 'agent' | 'service' | 'unknown';
 
 /**
  * Subject record: identity and classification
@@ -40,7 +42,7 @@ export type SubjectType = 'human' | 'org' | 'agent';
  *
  * Invariants:
  * - `id` is REQUIRED (non-empty string)
- * - `type` is REQUIRED (one of: human, org, agent)
+ * - `type` is REQUIRED (one of: human, org, agent, service, unknown)
  * - `labels` if present must be non-empty strings
  */
 export interface SubjectProfile {
@@ -339,14 +339,14 @@ export const PaymentEvidenceSchema = z
 /**
  * Subject type schema
  */
-export const SubjectTypeSchema = z.enum(['human', 'org', 'agent']);
+export const SubjectTypeSchema = z.enum(['human', 'org', 'agent', 'service', 'unknown']);
 
 /**
  * Subject profile schema
  *
  * Invariants:
  * - id is required (non-empty string)
- * - type is required (human, org, or agent)
+ * - type is required (human, org, agent, service, or unknown)
  * - labels if present must be non-empty strings
  */
 export const SubjectProfileSchema = z
@@ -0,0 +1,57 @@
+package peac
+
+import (
+	"fmt"
+
+	"github.com/peacprotocol/peac/sdks/go/policy"
+)
+
+// VerifyLocalAndAuthorize verifies a record with VerifyLocal, builds a policy
+// evaluation context from its verified claims with contextFor, and enforces
+// pol's decision for it. The record counts as a verified receipt (so a
+// Review decision is satisfied) when it meets the matched rule's receipt
+// requirements, checked with CheckReceiptRequirements; otherwise the result
+// is the rule's 402 challenge. A nil contextFor evaluates an empty context.
+//
+// When verification fails, the enforcement result is nil and the policy is
+// not evaluated: respond from the failed VerifyLocalResult instead.
+func VerifyLocalAndAuthorize(receiptJWS string, opts VerifyLocalOptions, pol *policy.PolicyDocument, contextFor func(*InteractionRecordClaims) *policy.EvaluationContext) (*VerifyLocalResult, *policy.EnforcementResult) {
+	result := VerifyLocal(receiptJWS, opts)
+	if !result.Valid {
+		return result, nil
+	}
+	var evalCtx *policy.EvaluationContext
+	if contextFor != nil {
+		evalCtx = contextFor(result.Claims)
+	}
+	eval := policy.Evaluate(pol, evalCtx)
+	met := CheckReceiptRequirements(result.Claims, eval.ReceiptRequirements) == nil
+	return result, policy.EnforceResult(eval, met)
+}
+
+// CheckReceiptRequirements reports whether verified claims satisfy a policy
+// rule's receipt requirements: the commerce extension must be in
+// req.Currency and carry at least req.MinAmount, checked as VerifyLocal's
+// ExpectedCurrency and MinAmount options would, and the record's
+// purpose_declared must equal req.Purpose when it is set (a missing or
+// different purpose fails with E_PURPOSE_MISMATCH). A nil req is always
+// satisfied.
+//
+// The returned error is a *PEACError, like ValidateClaims returns.
+func CheckReceiptRequirements(claims *InteractionRecordClaims, req *policy.ReceiptRequirements) error {
+	if req == nil {
+		return nil
+	}
+	if claims == nil {
+		return NewPEACError(ErrInvalidFormat, "claims are required").WithDetail("check", "claims")
+	}
+	opts := VerifyLocalOptions{ExpectedCurrency: req.Currency, MinAmount: req.MinAmount}
+	if check, code, msg := checkPayment(claims, opts); code != "" {
+		return NewPEACError(ErrorCode(code), msg).WithDetail("check", check)
+	}
+	if req.Purpose != "" && claims.PurposeDeclared != string(req.Purpose) {
+		return NewPEACError(ErrPurposeMismatch, fmt.Sprintf("purpose_declared %q does not match required purpose %q", claims.PurposeDeclared, req.Purpose)).
+			WithDetail("check", "purpose_declared")
+	}
+	return nil
+}
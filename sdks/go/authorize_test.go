@@ -0,0 +1,135 @@
+package peac
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/peacprotocol/peac/sdks/go/jws"
+	"github.com/peacprotocol/peac/sdks/go/policy"
+)
+
+func TestVerifyLocalAndAuthorize(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	issued, err := Issue(IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/test",
+		Sub:        "agent:crawler",
+		SigningKey: key,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pol := &policy.PolicyDocument{
+		Version: policy.PolicyVersion,
+		Rules: []policy.PolicyRule{
+			{Name: "crawlers-pay", Subject: &policy.SubjectMatcher{ID: "agent:*"}, Decision: policy.Review},
+		},
+		Defaults: &policy.PolicyDefaults{Decision: policy.Deny},
+	}
+	contextFor := func(c *InteractionRecordClaims) *policy.EvaluationContext {
+		return &policy.EvaluationContext{Subject: &policy.Subject{ID: c.Sub}}
+	}
+
+	result, enforcement := VerifyLocalAndAuthorize(issued.JWS, VerifyLocalOptions{PublicKey: key.PublicKey()}, pol, contextFor)
+	if !result.Valid {
+		t.Fatalf("VerifyLocal failed: %s", result.ErrorMessage)
+	}
+	if !enforcement.Allowed || enforcement.StatusCode != http.StatusOK {
+		t.Errorf("review with verified receipt: allowed=%v status=%d, want allowed 200", enforcement.Allowed, enforcement.StatusCode)
+	}
+
+	// No context: the default applies.
+	_, enforcement = VerifyLocalAndAuthorize(issued.JWS, VerifyLocalOptions{PublicKey: key.PublicKey()}, pol, nil)
+	if enforcement.Allowed || enforcement.StatusCode != http.StatusForbidden {
+		t.Errorf("default deny: allowed=%v status=%d, want 403", enforcement.Allowed, enforcement.StatusCode)
+	}
+
+	other, _ := jws.GenerateSigningKey("key-1")
+	result, enforcement = VerifyLocalAndAuthorize(issued.JWS, VerifyLocalOptions{PublicKey: other.PublicKey()}, pol, contextFor)
+	if result.Valid || result.ErrorCode != "E_INVALID_SIGNATURE" || enforcement != nil {
+		t.Errorf("bad signature: valid=%v code=%s enforcement=%v, want E_INVALID_SIGNATURE and nil", result.Valid, result.ErrorCode, enforcement)
+	}
+}
+
+func TestVerifyLocalAndAuthorize_ReceiptRequirements(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	issue := func(amount string) string {
+		issued, err := Issue(IssueOptions{
+			Iss:        "https://example.com",
+			Kind:       KindEvidence,
+			Type:       "org.peacprotocol/payment",
+			SigningKey: key,
+			Extensions: map[string]any{CommerceExtensionKey: map[string]any{
+				"payment_rail": "x402", "amount_minor": amount, "currency": "USD",
+			}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return issued.JWS
+	}
+	pol := &policy.PolicyDocument{
+		Version: policy.PolicyVersion,
+		Rules: []policy.PolicyRule{{
+			Name:                "paid",
+			Decision:            policy.Review,
+			ReceiptRequirements: &policy.ReceiptRequirements{MinAmount: 500, Currency: "USD"},
+		}},
+	}
+	opts := VerifyLocalOptions{PublicKey: key.PublicKey()}
+
+	_, enforcement := VerifyLocalAndAuthorize(issue("1000"), opts, pol, nil)
+	if !enforcement.Allowed || enforcement.StatusCode != http.StatusOK {
+		t.Errorf("sufficient payment: allowed=%v status=%d, want allowed 200", enforcement.Allowed, enforcement.StatusCode)
+	}
+
+	result, enforcement := VerifyLocalAndAuthorize(issue("100"), opts, pol, nil)
+	if !result.Valid {
+		t.Fatalf("VerifyLocal failed: %s", result.ErrorMessage)
+	}
+	if enforcement.Allowed || enforcement.StatusCode != http.StatusPaymentRequired {
+		t.Errorf("short payment: allowed=%v status=%d, want 402", enforcement.Allowed, enforcement.StatusCode)
+	}
+	if got, want := enforcement.Headers.Get("WWW-Authenticate"), policy.BuildChallenge(pol.Rules[0].ReceiptRequirements); got != want {
+		t.Errorf("challenge = %q, want %q", got, want)
+	}
+}
+
+func TestCheckReceiptRequirements(t *testing.T) {
+	claims := &InteractionRecordClaims{PurposeDeclared: string(policy.PurposeCrawl), Ext: map[string]any{CommerceExtensionKey: map[string]any{
+		"payment_rail": "x402", "amount_minor": "1000", "currency": "USD",
+	}}}
+	tests := []struct {
+		name     string
+		claims   *InteractionRecordClaims
+		req      *policy.ReceiptRequirements
+		wantCode ErrorCode
+	}{
+		{"nil requirements", claims, nil, ""},
+		{"met", claims, &policy.ReceiptRequirements{MinAmount: 1000, Currency: "USD"}, ""},
+		{"purpose only", claims, &policy.ReceiptRequirements{Purpose: policy.PurposeCrawl}, ""},
+		{"wrong purpose", claims, &policy.ReceiptRequirements{Purpose: policy.PurposeTrain}, ErrPurposeMismatch},
+		{"no purpose", &InteractionRecordClaims{}, &policy.ReceiptRequirements{Purpose: policy.PurposeCrawl}, ErrPurposeMismatch},
+		{"amount short", claims, &policy.ReceiptRequirements{MinAmount: 1001, Currency: "USD"}, "E_AMOUNT_MISMATCH"},
+		{"wrong currency", claims, &policy.ReceiptRequirements{Currency: "EUR"}, "E_CURRENCY_MISMATCH"},
+		{"no commerce", &InteractionRecordClaims{}, &policy.ReceiptRequirements{Currency: "USD"}, "E_CURRENCY_MISMATCH"},
+		{"nil claims", nil, &policy.ReceiptRequirements{Currency: "USD"}, ErrInvalidFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckReceiptRequirements(tt.claims, tt.req)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("CheckReceiptRequirements() = %v, want nil", err)
+				}
+				return
+			}
+			var pe *PEACError
+			if !errors.As(err, &pe) || pe.Code != tt.wantCode {
+				t.Errorf("CheckReceiptRequirements() = %v, want code %s", err, tt.wantCode)
+			}
+		})
+	}
+}
@@ -1,20 +1,185 @@
 package peac
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
 // InteractionRecordClaims represents claims in a signed interaction record
 // (typ: interaction-record+jwt, Wire 0.2).
 type InteractionRecordClaims struct {
-	Iss         string         `json:"iss"`
-	Sub         string         `json:"sub,omitempty"`
-	Iat         int64          `json:"iat"`
-	Exp         int64          `json:"exp,omitempty"`
-	Rid         string         `json:"rid"`
-	Kind        string         `json:"kind"`
-	Type        string         `json:"type"`
-	PeacVersion string         `json:"peac_version"`
-	Pillars     []string       `json:"pillars,omitempty"`
-	Actor       *ActorBinding  `json:"actor,omitempty"`
-	Ext         map[string]any `json:"ext,omitempty"`
-	Peac        *PolicyBlock   `json:"policy,omitempty"`
+	Iss             string         `json:"iss"`
+	Sub             string         `json:"sub,omitempty"`
+	Iat             int64          `json:"iat"`
+	Exp             int64          `json:"exp,omitempty"`
+	Rid             string         `json:"rid"`
+	Jti             string         `json:"jti,omitempty"`
+	Kind            string         `json:"kind"`
+	Type            string         `json:"type"`
+	PeacVersion     string         `json:"peac_version"`
+	Pillars         []string       `json:"pillars,omitempty"`
+	PurposeDeclared string         `json:"purpose_declared,omitempty"`
+	Actor           *ActorBinding  `json:"actor,omitempty"`
+	Ext             map[string]any `json:"ext,omitempty"`
+	Peac            *PolicyBlock   `json:"policy,omitempty"`
+
+	// Extra holds top-level claims this struct does not model, such as
+	// organization-specific claims, captured by VerifyLocal as raw JSON.
+	// Known claims are never included. It is not serialized: Issue does not
+	// emit it. Use GetExtra to decode an entry.
+	//
+	// The Wire 0.2 envelope is closed, so a record with Extra entries is
+	// not valid Wire 0.2. Extra is only populated when StrictClaims is off;
+	// VerifyLocalOptions.StrictClaims is the recommended setting.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// ReceiptID returns the record's identifier: rid, or the RFC 7519 jti used
+// by records from other Wire 0.2 issuers when rid is absent. VerifyLocal
+// rejects records whose rid and jti disagree.
+func (c *InteractionRecordClaims) ReceiptID() string {
+	if c.Rid != "" {
+		return c.Rid
+	}
+	return c.Jti
+}
+
+// knownClaimNames are the JSON names of the claims InteractionRecordClaims
+// models, excluded from Extra.
+var knownClaimNames = func() map[string]bool {
+	names := make(map[string]bool)
+	t := reflect.TypeFor[InteractionRecordClaims]()
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}()
+
+// GetExtra decodes the custom top-level claim name into out. It returns an
+// error wrapping ErrClaimNotFound when the record does not carry the claim.
+// A record carrying any such claim is not valid Wire 0.2 (see Extra).
+func (c *InteractionRecordClaims) GetExtra(name string, out any) error {
+	raw, ok := c.Extra[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrClaimNotFound, name)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// extraClaims returns the top-level claims in payload that are not known
+// claims, or nil if there are none.
+func extraClaims(payload []byte) (map[string]json.RawMessage, error) {
+	if onlyKnownClaimNames(payload) {
+		return nil, nil
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &all); err != nil {
+		return nil, err
+	}
+	for name := range all {
+		if knownClaimNames[name] {
+			delete(all, name)
+		}
+	}
+	if len(all) == 0 {
+		return nil, nil
+	}
+	return all, nil
+}
+
+// onlyKnownClaimNames reports, without allocating, whether every top-level
+// member name in payload is a known claim, so the common record with no
+// custom claims skips decoding into a map. payload must be valid JSON. A
+// name containing an escape is treated as unknown, which only costs the
+// slow path.
+func onlyKnownClaimNames(payload []byte) bool {
+	i := skipJSONSpace(payload, 0)
+	if i >= len(payload) || payload[i] != '{' {
+		return false
+	}
+	i++
+	for {
+		i = skipJSONSpace(payload, i)
+		if i >= len(payload) {
+			return false
+		}
+		switch payload[i] {
+		case '}':
+			return true
+		case ',':
+			i++
+			continue
+		case '"':
+		default:
+			return false
+		}
+		end := skipJSONString(payload, i)
+		name := payload[i+1 : end-1]
+		if bytes.IndexByte(name, '\\') >= 0 || !knownClaimNames[string(name)] {
+			return false
+		}
+		i = skipJSONSpace(payload, end) + 1 // past ':'
+		i = skipJSONValue(payload, i)
+	}
+}
+
+// skipJSONSpace returns the index of the first non-whitespace byte at or
+// after i.
+func skipJSONSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// skipJSONString returns the index just past the string starting at the
+// opening quote data[i].
+func skipJSONString(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(data)
+}
+
+// skipJSONValue returns the index of the ',' or closing bracket that ends
+// the value starting at or after i.
+func skipJSONValue(data []byte, i int) int {
+	depth := 0
+	for i < len(data) {
+		switch data[i] {
+		case '"':
+			i = skipJSONString(data, i)
+			continue
+		case '{', '[':
+			depth++
+		case '}', ']':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				return i
+			}
+		}
+		i++
+	}
+	return i
 }
 
 // ActorBinding represents the top-level actor field.
@@ -30,3 +195,22 @@ type PolicyBlock struct {
 	URI     string `json:"uri,omitempty"`
 	Version string `json:"version,omitempty"`
 }
+
+// IsExpired reports whether the record's exp is at or before now. Records
+// without exp never expire.
+func (c *InteractionRecordClaims) IsExpired(now time.Time) bool {
+	return c.Exp > 0 && !now.Before(time.Unix(c.Exp, 0))
+}
+
+// TimeRemaining returns the time left until exp, or zero if already expired.
+// The second return is false when the record has no exp.
+func (c *InteractionRecordClaims) TimeRemaining(now time.Time) (time.Duration, bool) {
+	if c.Exp <= 0 {
+		return 0, false
+	}
+	remaining := time.Unix(c.Exp, 0).Sub(now)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, true
+}
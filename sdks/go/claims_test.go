@@ -0,0 +1,59 @@
+package peac
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInteractionRecordClaims_Expiry(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name          string
+		exp           int64
+		wantExpired   bool
+		wantRemaining time.Duration
+		wantHasExp    bool
+	}{
+		{"no exp", 0, false, 0, false},
+		{"future", now.Add(time.Minute).Unix(), false, time.Minute, true},
+		{"exactly now", now.Unix(), true, 0, true},
+		{"past", now.Add(-time.Minute).Unix(), true, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &InteractionRecordClaims{Exp: tt.exp}
+			if got := c.IsExpired(now); got != tt.wantExpired {
+				t.Errorf("IsExpired() = %v, want %v", got, tt.wantExpired)
+			}
+			remaining, ok := c.TimeRemaining(now)
+			if ok != tt.wantHasExp || remaining != tt.wantRemaining {
+				t.Errorf("TimeRemaining() = (%v, %v), want (%v, %v)", remaining, ok, tt.wantRemaining, tt.wantHasExp)
+			}
+		})
+	}
+}
+
+func TestOnlyKnownClaimNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    bool
+	}{
+		{"known only", `{"iss":"https://a.example","iat":1,"ext":{"x":{"y":[1,"}",{"z":null}]}},"kind":"evidence"}`, true},
+		{"empty object", ` { } `, true},
+		{"unknown name", `{"iss":"https://a.example","custom":true}`, false},
+		{"unknown after nested value", `{"ext":{"iss":"x"},"custom":1}`, false},
+		{"escaped name", `{"\u0069ss":"https://a.example"}`, false},
+		{"quote in value", `{"sub":"a\"b","iat":2}`, true},
+		{"not an object", `[1]`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := onlyKnownClaimNames([]byte(tt.payload)); got != tt.want {
+				t.Errorf("onlyKnownClaimNames(%s) = %v, want %v", tt.payload, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	peac "github.com/peacprotocol/peac/sdks/go"
+	"github.com/peacprotocol/peac/sdks/go/jws"
+)
+
+// DefaultRefreshBefore is how long before exp a cached receipt stops being
+// served, leaving headroom for network latency and clock skew.
+const DefaultRefreshBefore = 30 * time.Second
+
+// ReceiptKey identifies a cached receipt.
+type ReceiptKey struct {
+	Issuer   string
+	Audience string
+	Resource string
+}
+
+type storeEntry struct {
+	jws       string
+	expiresAt time.Time // zero when the receipt has no exp
+}
+
+// ReceiptStore caches receipts so a client can reuse a still-valid receipt
+// across requests instead of obtaining a fresh one each time. It is safe for
+// concurrent use.
+//
+// Entries are served until RefreshBefore ahead of the receipt's exp. Receipts
+// without exp are cached until deleted.
+type ReceiptStore struct {
+	mu            sync.Mutex
+	entries       map[ReceiptKey]storeEntry
+	refreshBefore time.Duration
+	clock         peac.Clock
+}
+
+// NewReceiptStore creates an empty store. If refreshBefore is zero,
+// DefaultRefreshBefore is used. If clock is nil, the system clock is used.
+func NewReceiptStore(refreshBefore time.Duration, clock peac.Clock) *ReceiptStore {
+	if refreshBefore == 0 {
+		refreshBefore = DefaultRefreshBefore
+	}
+	if clock == nil {
+		clock = peac.DefaultClock()
+	}
+	return &ReceiptStore{
+		entries:       make(map[ReceiptKey]storeEntry),
+		refreshBefore: refreshBefore,
+		clock:         clock,
+	}
+}
+
+// Put caches receiptJWS under key. The payload is decoded (not verified) to
+// read exp; receipts that are already within the refresh window are rejected.
+func (s *ReceiptStore) Put(key ReceiptKey, receiptJWS string) error {
+	parsed, err := jws.Parse(receiptJWS)
+	if err != nil {
+		return fmt.Errorf("invalid receipt: %w", err)
+	}
+	var claims peac.InteractionRecordClaims
+	if err := json.Unmarshal(parsed.Payload, &claims); err != nil {
+		return fmt.Errorf("invalid receipt claims: %w", err)
+	}
+
+	entry := storeEntry{jws: receiptJWS}
+	if remaining, ok := claims.TimeRemaining(s.clock.Now()); ok {
+		if remaining <= s.refreshBefore {
+			return fmt.Errorf("receipt expires within refresh window (%s)", remaining)
+		}
+		entry.expiresAt = time.Unix(claims.Exp, 0)
+	}
+
+	s.mu.Lock()
+	s.entries[key] = entry
+	s.mu.Unlock()
+	return nil
+}
+
+// Get returns the cached receipt for key if it is still outside the refresh
+// window. Stale entries are evicted on access.
+func (s *ReceiptStore) Get(key ReceiptKey) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return "", false
+	}
+	if s.stale(entry, s.clock.Now()) {
+		delete(s.entries, key)
+		return "", false
+	}
+	return entry.jws, true
+}
+
+// Delete removes the cached receipt for key.
+func (s *ReceiptStore) Delete(key ReceiptKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+}
+
+// Prune removes every entry that is within its refresh window.
+func (s *ReceiptStore) Prune() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	for key, entry := range s.entries {
+		if s.stale(entry, now) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// Len returns the number of cached entries, including stale ones not yet
+// pruned.
+func (s *ReceiptStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+func (s *ReceiptStore) stale(entry storeEntry, now time.Time) bool {
+	return !entry.expiresAt.IsZero() && !now.Add(s.refreshBefore).Before(entry.expiresAt)
+}
+
+// Provider returns a ReceiptProvider for Transport that serves cached
+// receipts and calls mint only on a cache miss, storing the result.
+func (s *ReceiptStore) Provider(keyFor func(*http.Request) ReceiptKey, mint ReceiptProvider) ReceiptProvider {
+	return func(r *http.Request) (string, error) {
+		key := keyFor(r)
+		if receipt, ok := s.Get(key); ok {
+			return receipt, nil
+		}
+		receipt, err := mint(r)
+		if err != nil || receipt == "" {
+			return receipt, err
+		}
+		// A receipt too close to expiry is still usable once; it is just
+		// not worth caching.
+		_ = s.Put(key, receipt)
+		return receipt, nil
+	}
+}
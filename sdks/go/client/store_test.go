@@ -0,0 +1,114 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	peac "github.com/peacprotocol/peac/sdks/go"
+	"github.com/peacprotocol/peac/sdks/go/jws"
+)
+
+func issueReceipt(t *testing.T, now time.Time, exp int64) string {
+	t.Helper()
+	key, err := jws.GenerateSigningKey("client-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := peac.IssueJWS(peac.IssueOptions{
+		Iss:        "https://publisher.example",
+		Kind:       peac.KindEvidence,
+		Type:       "org.peacprotocol/test",
+		SigningKey: key,
+		Exp:        exp,
+		Clock:      peac.FixedClock{Time: now},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestReceiptStore_ServesUntilRefreshWindow(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	receipt := issueReceipt(t, now, now.Add(5*time.Minute).Unix())
+	key := ReceiptKey{Issuer: "https://publisher.example", Resource: "/articles/1"}
+
+	clock := &peac.FixedClock{Time: now}
+	store := NewReceiptStore(time.Minute, clock)
+	if err := store.Put(key, receipt); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	if got, ok := store.Get(key); !ok || got != receipt {
+		t.Fatal("expected cached receipt")
+	}
+
+	// Inside the refresh window: evicted.
+	clock.Time = now.Add(4*time.Minute + 30*time.Second)
+	if _, ok := store.Get(key); ok {
+		t.Error("expected receipt to be evicted inside refresh window")
+	}
+	if store.Len() != 0 {
+		t.Errorf("Len() = %d, want 0 after eviction", store.Len())
+	}
+}
+
+func TestReceiptStore_NoExpCachedUntilDeleted(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	receipt := issueReceipt(t, now, 0)
+	key := ReceiptKey{Issuer: "https://publisher.example"}
+
+	store := NewReceiptStore(0, peac.FixedClock{Time: now.Add(365 * 24 * time.Hour)})
+	if err := store.Put(key, receipt); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.Get(key); !ok {
+		t.Fatal("receipt without exp should stay cached")
+	}
+	store.Delete(key)
+	if _, ok := store.Get(key); ok {
+		t.Error("expected receipt to be deleted")
+	}
+}
+
+func TestReceiptStore_RejectsNearlyExpired(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	receipt := issueReceipt(t, now, now.Add(10*time.Second).Unix())
+
+	store := NewReceiptStore(time.Minute, peac.FixedClock{Time: now})
+	if err := store.Put(ReceiptKey{}, receipt); err == nil {
+		t.Error("expected Put() to reject receipt inside refresh window")
+	}
+}
+
+func TestReceiptStore_ProviderMintsOnce(t *testing.T) {
+	now := time.Now()
+	receipt := issueReceipt(t, now, now.Add(time.Hour).Unix())
+	mints := 0
+
+	store := NewReceiptStore(0, nil)
+	provider := store.Provider(
+		func(r *http.Request) ReceiptKey { return ReceiptKey{Resource: r.URL.Path} },
+		func(*http.Request) (string, error) {
+			mints++
+			return receipt, nil
+		},
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer srv.Close()
+	httpClient := &http.Client{Transport: NewTransport(nil, provider)}
+	for i := 0; i < 3; i++ {
+		resp, err := httpClient.Get(srv.URL + "/articles/1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	if mints != 1 {
+		t.Errorf("mint called %d times, want 1", mints)
+	}
+}
@@ -0,0 +1,114 @@
+// Package client provides client-side helpers for agents calling
+// PEAC-protected publishers. It is the counterpart to the server-side
+// middleware package.
+//
+// Usage:
+//
+//	import peacclient "github.com/peacprotocol/peac/sdks/go/client"
+//
+//	tr := peacclient.NewTransport(http.DefaultTransport, func(r *http.Request) (string, error) {
+//	    return receiptFor(r.URL)
+//	})
+//	tr.Hosts = []string{"publisher.example"}
+//	httpClient := &http.Client{Transport: tr}
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultHeaderName is the request header the transport sets by default.
+// It matches the header the server middleware reads.
+const DefaultHeaderName = "PEAC-Receipt"
+
+// ReceiptProvider returns the receipt JWS to attach to an outbound request.
+// Returning an empty string sends the request without a receipt.
+type ReceiptProvider func(*http.Request) (string, error)
+
+// Transport is an http.RoundTripper that attaches PEAC receipts to outbound
+// requests. Configure the exported fields before first use; a Transport is
+// safe for concurrent use once configured.
+type Transport struct {
+	// Base is the underlying RoundTripper (default: http.DefaultTransport).
+	Base http.RoundTripper
+
+	// Provider supplies the receipt for each request (required).
+	Provider ReceiptProvider
+
+	// HeaderName is the header to set (default: DefaultHeaderName).
+	HeaderName string
+
+	// Bearer prefixes the receipt with "Bearer " when set.
+	Bearer bool
+
+	// Hosts restricts attachment to requests whose host matches one of
+	// these names (case-insensitive, port ignored). Empty attaches to
+	// every request.
+	Hosts []string
+}
+
+// NewTransport creates a Transport that wraps base and attaches receipts
+// obtained from provider. If base is nil, http.DefaultTransport is used.
+func NewTransport(base http.RoundTripper, provider ReceiptProvider) *Transport {
+	return &Transport{
+		Base:       base,
+		Provider:   provider,
+		HeaderName: DefaultHeaderName,
+	}
+}
+
+// RoundTrip implements http.RoundTripper. The caller's request is never
+// mutated; a clone carries the receipt header.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if t.Provider == nil || !t.matchesHost(req) {
+		return base.RoundTrip(req)
+	}
+
+	receipt, err := t.Provider(req)
+	if err != nil {
+		closeBody(req)
+		return nil, fmt.Errorf("peac receipt provider: %w", err)
+	}
+	if receipt == "" {
+		return base.RoundTrip(req)
+	}
+
+	headerName := t.HeaderName
+	if headerName == "" {
+		headerName = DefaultHeaderName
+	}
+	if t.Bearer {
+		receipt = "Bearer " + receipt
+	}
+
+	clone := req.Clone(req.Context())
+	clone.Header.Set(headerName, receipt)
+	return base.RoundTrip(clone)
+}
+
+// matchesHost reports whether the request's host is in t.Hosts.
+func (t *Transport) matchesHost(req *http.Request) bool {
+	if len(t.Hosts) == 0 {
+		return true
+	}
+	host := req.URL.Hostname()
+	for _, h := range t.Hosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// closeBody closes the request body, as RoundTrip must do on error.
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newEchoServer(t *testing.T, headerName string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Seen", r.Header.Get(headerName))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestTransport_AttachesReceipt(t *testing.T) {
+	srv := newEchoServer(t, DefaultHeaderName)
+	tr := NewTransport(nil, func(*http.Request) (string, error) { return "jws-token", nil })
+	httpClient := &http.Client{Transport: tr}
+
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if got := resp.Header.Get("X-Seen"); got != "jws-token" {
+		t.Errorf("server saw %q, want jws-token", got)
+	}
+	if req.Header.Get(DefaultHeaderName) != "" {
+		t.Error("caller's request must not be mutated")
+	}
+}
+
+func TestTransport_BearerAndCustomHeader(t *testing.T) {
+	srv := newEchoServer(t, "Authorization")
+	tr := NewTransport(nil, func(*http.Request) (string, error) { return "jws-token", nil })
+	tr.HeaderName = "Authorization"
+	tr.Bearer = true
+
+	resp, err := (&http.Client{Transport: tr}).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if got := resp.Header.Get("X-Seen"); got != "Bearer jws-token" {
+		t.Errorf("server saw %q, want Bearer jws-token", got)
+	}
+}
+
+func TestTransport_HostFilter(t *testing.T) {
+	srv := newEchoServer(t, DefaultHeaderName)
+	called := false
+	tr := NewTransport(nil, func(*http.Request) (string, error) {
+		called = true
+		return "jws-token", nil
+	})
+	tr.Hosts = []string{"publisher.example"}
+
+	resp, err := (&http.Client{Transport: tr}).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if called {
+		t.Error("provider should not be called for non-matching host")
+	}
+	if got := resp.Header.Get("X-Seen"); got != "" {
+		t.Errorf("server saw %q, want no receipt", got)
+	}
+}
+
+func TestTransport_ProviderError(t *testing.T) {
+	srv := newEchoServer(t, DefaultHeaderName)
+	wantErr := errors.New("no receipt")
+	tr := NewTransport(nil, func(*http.Request) (string, error) { return "", wantErr })
+
+	_, err := (&http.Client{Transport: tr}).Get(srv.URL)
+	if !errors.Is(err, wantErr) {
+		t.Errorf("err = %v, want wrapping %v", err, wantErr)
+	}
+}
@@ -1,6 +1,7 @@
 package peac
 
 import (
+	"sync"
 	"time"
 )
 
@@ -32,6 +33,45 @@ func (c FixedClock) Now() time.Time {
 	return c.Time
 }
 
+// AdvancingClock returns a time that moves by a fixed step on every call to
+// Now, starting at its start time. It is safe for concurrent use. Create one
+// with NewAdvancingClock, or with NewRetreatingClock to move backward.
+type AdvancingClock struct {
+	mu   sync.Mutex
+	next time.Time
+	step time.Duration
+}
+
+// NewAdvancingClock returns a clock whose first Now is start and each later
+// Now is step further on. It panics if step is negative; use
+// NewRetreatingClock for time that runs backward.
+func NewAdvancingClock(start time.Time, step time.Duration) *AdvancingClock {
+	if step < 0 {
+		panic("peac: NewAdvancingClock with negative step; use NewRetreatingClock")
+	}
+	return &AdvancingClock{next: start, step: step}
+}
+
+// NewRetreatingClock returns a clock whose first Now is start and each later
+// Now is step earlier. It is meant for clock-skew tests, e.g. issuing a
+// record whose iat is later than the verifier's now. UUIDv7 IDs generated
+// with it do not sort in call order. It panics if step is negative.
+func NewRetreatingClock(start time.Time, step time.Duration) *AdvancingClock {
+	if step < 0 {
+		panic("peac: NewRetreatingClock with negative step; the step is subtracted")
+	}
+	return &AdvancingClock{next: start, step: -step}
+}
+
+// Now returns the current time and moves the clock by its step.
+func (c *AdvancingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.next
+	c.next = now.Add(c.step)
+	return now
+}
+
 // defaultClock is the package-level default clock.
 var defaultClock Clock = RealClock{}
 
@@ -1,6 +1,7 @@
 package peac
 
 import (
+	"sync"
 	"testing"
 	"time"
 )
@@ -33,6 +34,67 @@ func TestFixedClock_Now(t *testing.T) {
 	}
 }
 
+func TestAdvancingClock_Now(t *testing.T) {
+	start := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	forward := NewAdvancingClock(start, time.Second)
+	if got := forward.Now(); !got.Equal(start) {
+		t.Errorf("first Now() = %v, want %v", got, start)
+	}
+	if got := forward.Now(); !got.Equal(start.Add(time.Second)) {
+		t.Errorf("second Now() = %v, want %v", got, start.Add(time.Second))
+	}
+
+	backward := NewRetreatingClock(start, time.Minute)
+	backward.Now()
+	if got := backward.Now(); !got.Equal(start.Add(-time.Minute)) {
+		t.Errorf("retreating second Now() = %v, want %v", got, start.Add(-time.Minute))
+	}
+
+	for name, newClock := range map[string]func(time.Time, time.Duration) *AdvancingClock{
+		"advancing":  NewAdvancingClock,
+		"retreating": NewRetreatingClock,
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s clock with negative step did not panic", name)
+				}
+			}()
+			newClock(start, -time.Second)
+		}()
+	}
+}
+
+func TestAdvancingClock_Concurrent(t *testing.T) {
+	start := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	clock := NewRetreatingClock(start, time.Second)
+
+	const n = 100
+	seen := make(chan time.Time, n)
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			seen <- clock.Now()
+		}()
+	}
+	wg.Wait()
+	close(seen)
+
+	unique := make(map[time.Time]bool)
+	for tm := range seen {
+		unique[tm] = true
+	}
+	if len(unique) != n {
+		t.Errorf("got %d distinct times from %d calls, want %d", len(unique), n, n)
+	}
+	if got := clock.Now(); !got.Equal(start.Add(-n * time.Second)) {
+		t.Errorf("Now() after %d calls = %v, want %v", n, got, start.Add(-n*time.Second))
+	}
+}
+
 func TestDefaultClock(t *testing.T) {
 	clock := DefaultClock()
 	if _, ok := clock.(RealClock); !ok {
@@ -44,4 +106,5 @@ func TestClock_Interface(t *testing.T) {
 	// Verify all clock types implement the interface
 	var _ Clock = RealClock{}
 	var _ Clock = FixedClock{}
+	var _ Clock = &AdvancingClock{}
 }
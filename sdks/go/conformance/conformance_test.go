@@ -1,12 +1,10 @@
-// Package conformance provides cross-language conformance tests for the PEAC Go SDK.
-//
-// Wire 0.1 conformance tests have been removed as part of the Interaction Record
-// rewrite. Interaction Record format (interaction-record+jwt) conformance vectors
-// and cross-language golden vector tests ship in PR4 (Go tests + CI).
 package conformance
 
 import (
 	"encoding/json"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
 	peac "github.com/peacprotocol/peac/sdks/go"
@@ -79,3 +77,68 @@ func TestInteractionRecordRoundtrip(t *testing.T) {
 		t.Fatalf("VerifyJWS() failed: %v", err)
 	}
 }
+
+// fixturesRoot returns specs/conformance/fixtures in the repository.
+func fixturesRoot(t *testing.T) string {
+	t.Helper()
+	_, thisFile, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("runtime.Caller failed")
+	}
+	return filepath.Join(filepath.Dir(thisFile), "..", "..", "..", "specs", "conformance", "fixtures")
+}
+
+// TestFixtures runs the shared spec fixture packs. The issue and valid packs
+// hold Wire 0.1 inputs and are reported as skipped.
+func TestFixtures(t *testing.T) {
+	for _, dir := range []string{"policy", "issue", "valid"} {
+		t.Run(dir, func(t *testing.T) {
+			RunFixtures(t, filepath.Join(fixturesRoot(t), dir))
+		})
+	}
+}
+
+// TestWire02Fixtures signs the shared Wire 0.2 receipt vectors and runs
+// them through Issue and VerifyLocal.
+func TestWire02Fixtures(t *testing.T) {
+	for _, file := range []string{"valid.json", "invalid.json"} {
+		RunFixtureFile(t, filepath.Join(fixturesRoot(t), "wire-02", file))
+	}
+}
+
+func TestLoadFixtures_Suite(t *testing.T) {
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"policy/evaluation.json", SuitePolicyEvaluation},
+		{"policy/validation.json", SuitePolicyValidation},
+		{"policy/enforcement.json", SuitePolicyEnforcement},
+		{"wire-02/valid.json", SuiteWire02},
+		{"wire-02/invalid.json", SuiteWire02},
+		{"issue/valid.json", ""},
+		{"valid/minimal-receipt.json", ""},
+	}
+	for _, tt := range tests {
+		pack, err := LoadFixtures(filepath.Join(fixturesRoot(t), tt.file))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.file, err)
+		}
+		if got := pack.Suite(); got != tt.want {
+			t.Errorf("%s: Suite() = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestRunFixture_ReportsID(t *testing.T) {
+	pack, err := LoadFixtures(filepath.Join(fixturesRoot(t), "policy", "evaluation.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := pack.Fixtures[0]
+	f.Expected.Decision = "maybe"
+	err = RunFixture(pack, f)
+	if err == nil || !strings.Contains(err.Error(), f.ID) {
+		t.Errorf("RunFixture() = %v, want mismatch naming %s", err, f.ID)
+	}
+}
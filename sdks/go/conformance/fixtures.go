@@ -0,0 +1,282 @@
+// Package conformance runs the spec conformance fixtures against the PEAC
+// Go SDK.
+//
+// RunFixtures loads the fixture packs under specs/conformance/fixtures, the
+// same files the TypeScript suite runs: {fixtures:[{id,input,expected}]}
+// with an optional shared test_policy, or valid_fixtures/invalid_fixtures
+// for document validation. The wire-02 receipt packs are signed and run
+// through Issue and VerifyLocal. Packs the Go SDK has no runner for, such
+// as the Wire 0.1 issue inputs, and fixtures for checks it does not
+// implement are skipped with the reason rather than passed.
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/peacprotocol/peac/sdks/go/policy"
+)
+
+// ErrUnsupported is reported, wrapped, by RunFixture for a fixture that
+// exercises a check the Go SDK does not implement.
+var ErrUnsupported = errors.New("not supported by the Go SDK")
+
+// Fixture pack suites, selected from the pack's shape.
+const (
+	SuitePolicyEvaluation  = "policy-evaluation"
+	SuitePolicyValidation  = "policy-validation"
+	SuitePolicyEnforcement = "policy-enforcement"
+	SuiteWire02            = "wire-02"
+)
+
+// FixturePack is the shape of a spec conformance fixture file.
+type FixturePack struct {
+	Comment         string                 `json:"$comment,omitempty"`
+	Version         string                 `json:"version,omitempty"`
+	TestPolicy      *policy.PolicyDocument `json:"test_policy,omitempty"`
+	Fixtures        []Fixture              `json:"fixtures,omitempty"`
+	ValidFixtures   []Fixture              `json:"valid_fixtures,omitempty"`
+	InvalidFixtures []Fixture              `json:"invalid_fixtures,omitempty"`
+}
+
+// Fixture is one golden case. Evaluation fixtures carry Context, validation
+// fixtures carry Policy, and the rest carry Input. Fixtures are identified
+// by ID, or by Name in packs such as wire-02 that have no IDs.
+type Fixture struct {
+	ID          string                    `json:"id,omitempty"`
+	Name        string                    `json:"name,omitempty"`
+	Type        string                    `json:"type,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Input       json.RawMessage           `json:"input,omitempty"`
+	Context     *policy.EvaluationContext `json:"context,omitempty"`
+	Policy      json.RawMessage           `json:"policy,omitempty"`
+	Expected    Expected                  `json:"expected"`
+}
+
+// Expected is a fixture's expected outcome. Each field is compared only when
+// set; a null matched_rule is checked through IsDefault. IssueContains,
+// MustFail, and Path describe schema rejections in the wire-02 packs, which
+// name the spec issue or field rather than a verifier error code.
+type Expected struct {
+	Valid         *bool             `json:"valid,omitempty"`
+	ErrorCode     string            `json:"error_code,omitempty"`
+	IssueContains string            `json:"issue_contains,omitempty"`
+	MustFail      bool              `json:"must_fail,omitempty"`
+	Path          string            `json:"path,omitempty"`
+	Decision      policy.Decision   `json:"decision,omitempty"`
+	MatchedRule   *string           `json:"matched_rule,omitempty"`
+	IsDefault     *bool             `json:"is_default,omitempty"`
+	Status        int               `json:"status,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+}
+
+// enforcementInput is the input of a policy enforcement fixture.
+type enforcementInput struct {
+	Decision        policy.Decision `json:"decision"`
+	ReceiptVerified *bool           `json:"receipt_verified"`
+}
+
+// LoadFixtures reads the fixture pack at path. Fixture IDs (or names, when
+// a fixture has no ID) must be unique within the pack.
+func LoadFixtures(path string) (*FixturePack, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var pack FixturePack
+	if err := json.Unmarshal(data, &pack); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	seen := make(map[string]bool)
+	for _, f := range pack.all() {
+		if f.key() == "" {
+			return nil, fmt.Errorf("%s: fixture has no id or name", filepath.Base(path))
+		}
+		if seen[f.key()] {
+			return nil, fmt.Errorf("%s: duplicate fixture id %q", filepath.Base(path), f.key())
+		}
+		seen[f.key()] = true
+	}
+	return &pack, nil
+}
+
+// Suite returns the suite the pack belongs to, or "" when the Go SDK has no
+// runner for it.
+func (p *FixturePack) Suite() string {
+	switch {
+	case p.TestPolicy != nil:
+		return SuitePolicyEvaluation
+	case len(p.ValidFixtures) > 0 || len(p.InvalidFixtures) > 0:
+		return SuitePolicyValidation
+	case len(p.Fixtures) > 0:
+		var in enforcementInput
+		if json.Unmarshal(p.Fixtures[0].Input, &in) == nil && in.Decision != "" && in.ReceiptVerified != nil {
+			return SuitePolicyEnforcement
+		}
+		var rec wire02Input
+		if json.Unmarshal(p.Fixtures[0].Input, &rec) == nil && len(rec.Claims) > 0 {
+			return SuiteWire02
+		}
+	}
+	return ""
+}
+
+// key returns the fixture's ID, or its name when it has none.
+func (f Fixture) key() string {
+	if f.ID != "" {
+		return f.ID
+	}
+	return f.Name
+}
+
+func (p *FixturePack) all() []Fixture {
+	all := append(append([]Fixture(nil), p.Fixtures...), p.ValidFixtures...)
+	return append(all, p.InvalidFixtures...)
+}
+
+// RunFixtures runs every *.json fixture pack in dir, in name order, with
+// RunFixtureFile. It fails the test if dir holds no packs.
+func RunFixtures(t *testing.T, dir string) {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no fixture packs in %s", dir)
+	}
+	for _, path := range paths {
+		RunFixtureFile(t, path)
+	}
+}
+
+// RunVectors is RunFixtures under the name the golden-vector runner was
+// introduced with.
+func RunVectors(t *testing.T, dir string) {
+	t.Helper()
+	RunFixtures(t, dir)
+}
+
+// RunFixtureFile runs the fixture pack at path as a subtest named by file,
+// with one subtest per fixture named by its ID or name. A pack without a Go
+// runner is skipped, as is a fixture whose run reports ErrUnsupported.
+func RunFixtureFile(t *testing.T, path string) {
+	t.Helper()
+	t.Run(filepath.Base(path), func(t *testing.T) {
+		pack, err := LoadFixtures(path)
+		if err != nil {
+			t.Fatalf("load fixtures: %v", err)
+		}
+		switch {
+		case len(pack.all()) == 0:
+			t.Skip("not a fixture pack")
+		case pack.Suite() == "":
+			t.Skipf("no Go runner for this pack: %s", pack.Comment)
+		}
+		for _, f := range pack.all() {
+			t.Run(f.key(), func(t *testing.T) {
+				err := RunFixture(pack, f)
+				switch {
+				case errors.Is(err, ErrUnsupported):
+					t.Skip(err)
+				case err != nil:
+					t.Error(err)
+				}
+			})
+		}
+	})
+}
+
+// RunFixture exercises one fixture of pack and returns an error naming the
+// fixture when the outcome differs from f.Expected.
+func RunFixture(pack *FixturePack, f Fixture) error {
+	var err error
+	switch pack.Suite() {
+	case SuitePolicyEvaluation:
+		err = runEvaluation(pack.TestPolicy, f)
+	case SuitePolicyValidation:
+		err = runValidation(f)
+	case SuitePolicyEnforcement:
+		err = runEnforcement(f)
+	case SuiteWire02:
+		err = runWire02(f)
+	default:
+		err = fmt.Errorf("no runner for this fixture pack")
+	}
+	if err != nil {
+		return fmt.Errorf("fixture %s: %w", f.key(), err)
+	}
+	return nil
+}
+
+func runEvaluation(doc *policy.PolicyDocument, f Fixture) error {
+	if f.Context == nil {
+		return fmt.Errorf("evaluation fixture needs context")
+	}
+	result := policy.Evaluate(doc, f.Context)
+	want := f.Expected
+	if want.Decision != "" && result.Decision != want.Decision {
+		return fmt.Errorf("decision = %q, want %q", result.Decision, want.Decision)
+	}
+	if want.MatchedRule != nil && result.MatchedRule != *want.MatchedRule {
+		return fmt.Errorf("matched rule = %q, want %q", result.MatchedRule, *want.MatchedRule)
+	}
+	if want.IsDefault != nil && result.IsDefault != *want.IsDefault {
+		return fmt.Errorf("is default = %v, want %v", result.IsDefault, *want.IsDefault)
+	}
+	return nil
+}
+
+// runValidation parses the fixture policy strictly, rejecting unknown
+// fields as the runner contract requires, then validates it.
+func runValidation(f Fixture) error {
+	code := ""
+	dec := json.NewDecoder(bytes.NewReader(f.Policy))
+	dec.DisallowUnknownFields()
+	var doc policy.PolicyDocument
+	if err := dec.Decode(&doc); err != nil {
+		code = policy.ErrCodeInvalidPolicy
+	} else if err := policy.Validate(&doc); err != nil {
+		var verr *policy.ValidationError
+		if !errors.As(err, &verr) {
+			return err
+		}
+		code = verr.Code
+	}
+	return checkValid(code == "", code, f.Expected)
+}
+
+func runEnforcement(f Fixture) error {
+	var in enforcementInput
+	if err := json.Unmarshal(f.Input, &in); err != nil || in.ReceiptVerified == nil {
+		return fmt.Errorf("enforcement fixture needs decision and receipt_verified")
+	}
+	result := policy.EnforceDecision(in.Decision, *in.ReceiptVerified)
+	if f.Expected.Status != 0 && result.StatusCode != f.Expected.Status {
+		return fmt.Errorf("status = %d, want %d", result.StatusCode, f.Expected.Status)
+	}
+	for name, want := range f.Expected.Headers {
+		if got := result.Headers.Get(name); got != want {
+			return fmt.Errorf("header %s = %q, want %q", name, got, want)
+		}
+	}
+	if len(f.Expected.Headers) == 0 && len(result.Headers) != 0 {
+		return fmt.Errorf("headers = %v, want none", result.Headers)
+	}
+	return nil
+}
+
+func checkValid(valid bool, code string, want Expected) error {
+	if want.Valid != nil && valid != *want.Valid {
+		return fmt.Errorf("valid = %v (code %q), want %v", valid, code, *want.Valid)
+	}
+	if want.ErrorCode != "" && code != want.ErrorCode {
+		return fmt.Errorf("error code = %q, want %q", code, want.ErrorCode)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package conformance
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	peac "github.com/peacprotocol/peac/sdks/go"
+	"github.com/peacprotocol/peac/sdks/go/jws"
+)
+
+// wire02Input is the input of a wire-02 fixture: the record's claims and
+// protected-header members to add or replace. A null override removes the
+// member.
+type wire02Input struct {
+	Claims          json.RawMessage            `json:"claims"`
+	HeaderOverrides map[string]json.RawMessage `json:"header_overrides,omitempty"`
+}
+
+// wire02Claims is the subset of Wire 0.2 claims IssueOptions can express.
+// A fixture carrying any other claim is only run through VerifyLocal.
+type wire02Claims struct {
+	PeacVersion     string             `json:"peac_version"`
+	Kind            string             `json:"kind"`
+	Type            string             `json:"type"`
+	Iss             string             `json:"iss"`
+	Iat             int64              `json:"iat"`
+	Jti             string             `json:"jti"`
+	Sub             string             `json:"sub"`
+	Pillars         []string           `json:"pillars"`
+	PurposeDeclared string             `json:"purpose_declared"`
+	Actor           *peac.ActorBinding `json:"actor"`
+	Policy          *peac.PolicyBlock  `json:"policy"`
+	Extensions      map[string]any     `json:"extensions"`
+}
+
+const wire02Kid = "wire02-fixture-key"
+
+// wire02Key signs every wire-02 fixture record. A fixed seed keeps the
+// signed records reproducible across runs.
+var wire02Key = ed25519.NewKeyFromSeed(bytes.Repeat([]byte{0x02}, ed25519.SeedSize))
+
+// verifierCodes maps spec error codes to the code VerifyLocal reports for
+// the same rejection: it folds JOSE header rejections into E_INVALID_FORMAT
+// and reports claims that do not match the typ as an unsupported version.
+var verifierCodes = map[string]string{
+	"E_JWS_EMBEDDED_KEY":      "E_INVALID_FORMAT",
+	"E_JWS_CRIT_REJECTED":     "E_INVALID_FORMAT",
+	"E_JWS_MISSING_KID":       "E_INVALID_FORMAT",
+	"E_JWS_B64_REJECTED":      "E_INVALID_FORMAT",
+	"E_JWS_ZIP_REJECTED":      "E_INVALID_FORMAT",
+	"E_WIRE_VERSION_MISMATCH": "E_UNSUPPORTED_WIRE_VERSION",
+}
+
+// wire02Gaps names the wire-02 fixtures whose rejection relies on a schema
+// rule Issue and VerifyLocal do not enforce, with the rule.
+var wire02Gaps = map[string]string{
+	"reject-kid-too-long":             "kid length limit",
+	"reject-uppercase-host":           "canonical https issuer",
+	"reject-default-port-443":         "canonical https issuer",
+	"reject-trailing-slash":           "canonical https issuer",
+	"reject-with-path":                "canonical https issuer",
+	"reject-http-scheme":              "issuer scheme check at verification",
+	"reject-spiffe-scheme":            "issuer scheme check at verification",
+	"reject-did-with-fragment":        "canonical did issuer",
+	"reject-did-with-path":            "canonical did issuer",
+	"reject-empty-type":               "type check at verification",
+	"reject-invalid-dns-type":         "type grammar",
+	"reject-unknown-pillar":           "pillar check at verification",
+	"reject-unsorted-pillars":         "sorted pillars",
+	"reject-duplicate-pillars":        "sorted pillars",
+	"reject-occurred-at-on-challenge": "occurred_at on challenge records",
+}
+
+// runWire02 signs the fixture claims with its header overrides and checks
+// VerifyLocal's outcome. When IssueOptions can express the claims, Issue
+// must accept or reject them the same way, and an issued record must
+// verify.
+func runWire02(f Fixture) error {
+	if rule, ok := wire02Gaps[f.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrUnsupported, rule)
+	}
+	var in wire02Input
+	if err := json.Unmarshal(f.Input, &in); err != nil || len(in.Claims) == 0 {
+		return fmt.Errorf("wire-02 fixture needs claims")
+	}
+	want := f.Expected
+	if code, ok := verifierCodes[want.ErrorCode]; ok {
+		want.ErrorCode = code
+	}
+	publicKey := wire02Key.Public().(ed25519.PublicKey)
+
+	compact, err := signWire02(in)
+	if err != nil {
+		return err
+	}
+	result := peac.VerifyLocal(compact, peac.VerifyLocalOptions{PublicKey: publicKey})
+	if err := checkValid(result.Valid, result.ErrorCode, want); err != nil {
+		return fmt.Errorf("VerifyLocal: %w: %s", err, result.ErrorMessage)
+	}
+
+	opts, ok := issueOptionsFor(in)
+	if !ok {
+		return nil
+	}
+	key, err := jws.NewSigningKey(wire02Key, wire02Kid)
+	if err != nil {
+		return err
+	}
+	opts.SigningKey = key
+	issued, err := peac.Issue(opts)
+	if want.Valid != nil && (err == nil) != *want.Valid {
+		return fmt.Errorf("Issue: err = %v, want valid %v", err, *want.Valid)
+	}
+	if err == nil {
+		if r := peac.VerifyLocal(issued.JWS, peac.VerifyLocalOptions{PublicKey: publicKey}); !r.Valid {
+			return fmt.Errorf("VerifyLocal of issued record: %s: %s", r.ErrorCode, r.ErrorMessage)
+		}
+	}
+	return nil
+}
+
+// signWire02 returns the fixture claims as a compact JWS signed with
+// wire02Key under an interaction-record+jwt header with overrides applied.
+func signWire02(in wire02Input) (string, error) {
+	header := map[string]json.RawMessage{
+		"alg": json.RawMessage(`"EdDSA"`),
+		"typ": json.RawMessage(`"` + peac.InteractionRecordTyp + `"`),
+		"kid": json.RawMessage(`"` + wire02Kid + `"`),
+	}
+	for name, value := range in.HeaderOverrides {
+		if string(value) == "null" {
+			delete(header, name)
+			continue
+		}
+		header[name] = value
+	}
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	input := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(in.Claims)
+	signature := ed25519.Sign(wire02Key, []byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// issueOptionsFor maps fixture claims to IssueOptions. It reports false when
+// the fixture overrides the header, carries a claim IssueOptions cannot
+// express, or names a wire version Issue does not emit.
+func issueOptionsFor(in wire02Input) (peac.IssueOptions, bool) {
+	if len(in.HeaderOverrides) > 0 {
+		return peac.IssueOptions{}, false
+	}
+	dec := json.NewDecoder(bytes.NewReader(in.Claims))
+	dec.DisallowUnknownFields()
+	var c wire02Claims
+	if err := dec.Decode(&c); err != nil || c.PeacVersion != peac.PeacVersion {
+		return peac.IssueOptions{}, false
+	}
+	return peac.IssueOptions{
+		Iss:             c.Iss,
+		Kind:            c.Kind,
+		Type:            c.Type,
+		Kid:             wire02Kid,
+		Sub:             c.Sub,
+		Pillars:         c.Pillars,
+		PurposeDeclared: c.PurposeDeclared,
+		Actor:           c.Actor,
+		Extensions:      c.Extensions,
+		Policy:          c.Policy,
+	}, true
+}
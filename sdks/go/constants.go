@@ -12,6 +12,19 @@ const (
 	KindChallenge = "challenge"
 )
 
+// Access extension group and the registered record type that carries it.
+const (
+	AccessExtensionKey = "org.peacprotocol/access"
+	TypeAccessDecision = "org.peacprotocol/access-decision"
+)
+
+// CommerceExtensionKey is the extension group for payment evidence.
+const CommerceExtensionKey = "org.peacprotocol/commerce"
+
+// MaxPurposeDeclaredLength is the longest purpose_declared value Wire 0.2
+// allows.
+const MaxPurposeDeclaredLength = 256
+
 // Pillar values (closed 10-pillar taxonomy).
 var ValidPillars = map[string]bool{
 	"access":      true,
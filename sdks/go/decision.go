@@ -0,0 +1,72 @@
+package peac
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/peacprotocol/peac/sdks/go/policy"
+)
+
+// IssueFromDecision issues an access-decision record for a policy evaluation
+// result, typically after a Review decision has been satisfied (for example by
+// payment).
+//
+// The decision is written to the org.peacprotocol/access extension. That
+// extension also requires resource and action, which the evaluation result
+// does not carry, so base.Extensions must already hold an access entry with
+// both fields:
+//
+//	base.Extensions = map[string]any{
+//	    peac.AccessExtensionKey: map[string]any{"resource": url, "action": "read"},
+//	}
+//
+// Kind, Type and Pillars default to evidence, TypeAccessDecision and
+// ["access"] when unset. base.Extensions is not modified.
+func IssueFromDecision(result *policy.EvaluationResult, base IssueOptions) (*IssueResult, error) {
+	opts, err := decisionIssueOptions(result, base)
+	if err != nil {
+		return nil, err
+	}
+	return Issue(opts)
+}
+
+func decisionIssueOptions(result *policy.EvaluationResult, base IssueOptions) (IssueOptions, error) {
+	if result == nil {
+		return base, &IssueError{Code: ErrCodeInvalidDecision, Message: "evaluation result is nil"}
+	}
+	switch result.Decision {
+	case policy.Allow, policy.Deny, policy.Review:
+	default:
+		return base, &IssueError{
+			Code:    ErrCodeInvalidDecision,
+			Message: fmt.Sprintf("invalid decision %q", result.Decision),
+			Details: map[string]any{"value": string(result.Decision)},
+		}
+	}
+
+	existing, _ := base.Extensions[AccessExtensionKey].(map[string]any)
+	if existing["resource"] == nil || existing["action"] == nil {
+		return base, &IssueError{
+			Code:    ErrCodeInvalidDecision,
+			Message: "access extension requires resource and action",
+			Field:   "Extensions." + AccessExtensionKey,
+		}
+	}
+	access := maps.Clone(existing)
+	access["decision"] = string(result.Decision)
+
+	opts := base
+	opts.Extensions = maps.Clone(base.Extensions)
+	opts.Extensions[AccessExtensionKey] = access
+
+	if opts.Kind == "" {
+		opts.Kind = KindEvidence
+	}
+	if opts.Type == "" {
+		opts.Type = TypeAccessDecision
+	}
+	if opts.Pillars == nil {
+		opts.Pillars = []string{"access"}
+	}
+	return opts, nil
+}
@@ -0,0 +1,74 @@
+package peac
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/peacprotocol/peac/sdks/go/policy"
+)
+
+func TestIssueFromDecision(t *testing.T) {
+	key := testSigningKey(t)
+	access := map[string]any{"resource": "https://publisher.example/articles/1", "action": "read"}
+	base := IssueOptions{
+		Iss:        "https://publisher.example",
+		SigningKey: key,
+		Extensions: map[string]any{AccessExtensionKey: access},
+	}
+	result := &policy.EvaluationResult{Decision: policy.Review, MatchedRule: "review-agents"}
+
+	issued, err := IssueFromDecision(result, base)
+	if err != nil {
+		t.Fatalf("IssueFromDecision() error = %v", err)
+	}
+
+	claims := issued.Claims
+	if claims.Kind != KindEvidence || claims.Type != TypeAccessDecision {
+		t.Errorf("kind/type = %s/%s, want %s/%s", claims.Kind, claims.Type, KindEvidence, TypeAccessDecision)
+	}
+	if len(claims.Pillars) != 1 || claims.Pillars[0] != "access" {
+		t.Errorf("pillars = %v, want [access]", claims.Pillars)
+	}
+	ext, _ := claims.Ext[AccessExtensionKey].(map[string]any)
+	if ext["decision"] != "review" || ext["resource"] != access["resource"] {
+		t.Errorf("access extension = %v", ext)
+	}
+	if _, ok := access["decision"]; ok {
+		t.Error("base extensions must not be modified")
+	}
+
+	verified := VerifyLocal(issued.JWS, VerifyLocalOptions{PublicKey: key.PublicKey()})
+	if !verified.Valid {
+		t.Fatalf("VerifyLocal() = %s: %s", verified.ErrorCode, verified.ErrorMessage)
+	}
+}
+
+func TestIssueFromDecision_Errors(t *testing.T) {
+	key := testSigningKey(t)
+	withAccess := map[string]any{AccessExtensionKey: map[string]any{"resource": "r", "action": "read"}}
+
+	tests := []struct {
+		name   string
+		result *policy.EvaluationResult
+		ext    map[string]any
+	}{
+		{"nil result", nil, withAccess},
+		{"unknown decision", &policy.EvaluationResult{Decision: "maybe"}, withAccess},
+		{"missing access extension", &policy.EvaluationResult{Decision: policy.Allow}, nil},
+		{"missing action", &policy.EvaluationResult{Decision: policy.Allow}, map[string]any{AccessExtensionKey: map[string]any{"resource": "r"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := IssueFromDecision(tt.result, IssueOptions{
+				Iss:        "https://publisher.example",
+				SigningKey: key,
+				Extensions: tt.ext,
+			})
+			var ie *IssueError
+			if !errors.As(err, &ie) || ie.Code != ErrCodeInvalidDecision {
+				t.Errorf("err = %v, want %s", err, ErrCodeInvalidDecision)
+			}
+		})
+	}
+}
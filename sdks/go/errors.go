@@ -9,19 +9,26 @@ var (
 	ErrInvalidType        = errors.New("type must be non-empty reverse-DNS or URI")
 	ErrMissingRequired    = errors.New("missing required field")
 	ErrUnsupportedVersion = errors.New("unsupported wire version")
+	ErrClaimNotFound      = errors.New("claim not present")
 )
 
 // Error code constants for issuance validation.
 const (
-	ErrCodeMissingIssuer = "MISSING_ISSUER"
-	ErrCodeMissingKind   = "MISSING_KIND"
-	ErrCodeMissingType   = "MISSING_TYPE"
-	ErrCodeMissingKey    = "MISSING_SIGNING_KEY"
-	ErrCodeMissingKid    = "MISSING_KEY_ID"
-	ErrCodeInvalidIss    = "INVALID_ISSUER"
-	ErrCodeInvalidKind   = "INVALID_KIND"
-	ErrCodeInvalidType   = "INVALID_TYPE"
-	ErrCodeInvalidPillar = "INVALID_PILLAR"
-	ErrCodeSignFailed    = "SIGN_FAILED"
-	ErrCodeIDGenFailed   = "ID_GEN_FAILED"
+	ErrCodeMissingIssuer   = "MISSING_ISSUER"
+	ErrCodeMissingKind     = "MISSING_KIND"
+	ErrCodeMissingType     = "MISSING_TYPE"
+	ErrCodeMissingKey      = "MISSING_SIGNING_KEY"
+	ErrCodeMissingKid      = "MISSING_KEY_ID"
+	ErrCodeInvalidIss      = "INVALID_ISSUER"
+	ErrCodeInvalidKind     = "INVALID_KIND"
+	ErrCodeInvalidType     = "INVALID_TYPE"
+	ErrCodeInvalidPillar   = "INVALID_PILLAR"
+	ErrCodeInvalidPurpose  = "INVALID_PURPOSE"
+	ErrCodeInvalidDecision = "INVALID_DECISION"
+	ErrCodeInvalidEvidence = "INVALID_EVIDENCE"
+	ErrCodeEvidenceTimeout = string(ErrEvidenceTimeout)
+	ErrCodeInvalidEnv      = "INVALID_ENV"
+	ErrCodeSignFailed      = "SIGN_FAILED"
+	ErrCodeIDGenFailed     = "ID_GEN_FAILED"
+	ErrCodeReceiptTooLarge = "RECEIPT_TOO_LARGE"
 )
@@ -3,10 +3,14 @@
 package evidence
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 // Limits defines the DoS protection limits for evidence validation.
@@ -35,6 +39,11 @@ type Limits struct {
 
 	// MaxTotalNodes is the maximum total number of nodes (default: 100000).
 	MaxTotalNodes int
+
+	// RejectEmptyKeys rejects objects containing an empty-string key with
+	// ErrCodeEmptyKey (default: false). JSON permits "", but it usually
+	// signals a serialization bug and makes error paths ambiguous.
+	RejectEmptyKeys bool
 }
 
 // DefaultLimits returns the default DoS protection limits.
@@ -75,6 +84,34 @@ func (l Limits) WithDefaults() Limits {
 	return l
 }
 
+// MergeWith returns a copy of l layered on top of base: positive values in l
+// win, zero/negative values fall back to base, and anything still unset falls
+// back to DefaultLimits(). Use this to apply per-call overrides on top of an
+// organization-wide baseline. RejectEmptyKeys is on if either l or base
+// turns it on.
+func (l Limits) MergeWith(base Limits) Limits {
+	if l.MaxBytes <= 0 {
+		l.MaxBytes = base.MaxBytes
+	}
+	if l.MaxDepth <= 0 {
+		l.MaxDepth = base.MaxDepth
+	}
+	if l.MaxArrayLength <= 0 {
+		l.MaxArrayLength = base.MaxArrayLength
+	}
+	if l.MaxObjectKeys <= 0 {
+		l.MaxObjectKeys = base.MaxObjectKeys
+	}
+	if l.MaxStringLength <= 0 {
+		l.MaxStringLength = base.MaxStringLength
+	}
+	if l.MaxTotalNodes <= 0 {
+		l.MaxTotalNodes = base.MaxTotalNodes
+	}
+	l.RejectEmptyKeys = l.RejectEmptyKeys || base.RejectEmptyKeys
+	return l.WithDefaults()
+}
+
 // ValidationError represents an evidence validation error.
 type ValidationError struct {
 	Code    string `json:"code"`
@@ -99,6 +136,7 @@ const (
 	ErrCodeTotalNodesTooLarge = "E_EVIDENCE_TOTAL_NODES_EXCEEDED"
 	ErrCodeInvalidJSON        = "E_EVIDENCE_INVALID_JSON"
 	ErrCodeNonFiniteNumber    = "E_EVIDENCE_NON_FINITE_NUMBER"
+	ErrCodeEmptyKey           = "E_EVIDENCE_EMPTY_KEY"
 )
 
 // Validate validates evidence JSON against DoS protection limits.
@@ -132,23 +170,48 @@ func Validate(data []byte, limits Limits) error {
 //
 // Note: For deterministic error paths across runs, object keys are processed
 // in sorted order. This ensures consistent error reporting for conformance testing.
+//
+// Traversal state is pooled and error paths are only built when a limit is
+// exceeded, so the common success case allocates very little.
 func ValidateValue(value any, limits Limits) error {
-	// Stack-based traversal to prevent recursion stack overflow
-	type stackItem struct {
-		value any
-		depth int
-		path  string
+	return validateValue(nil, value, limits)
+}
+
+// ctxCheckInterval is how many nodes ValidateValueContext visits between
+// checks of ctx.Err().
+const ctxCheckInterval = 1024
+
+// ValidateValueContext is ValidateValue bounded by ctx. The context is checked
+// before traversal and every ctxCheckInterval nodes; if it is done, ctx.Err()
+// is returned unwrapped so callers can test it with errors.Is. Use it to cap
+// evidence-validation latency on a request path.
+func ValidateValueContext(ctx context.Context, value any, limits Limits) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
+	return validateValue(ctx, value, limits)
+}
 
-	stack := []stackItem{{value: value, depth: 0, path: ""}}
+// validateValue implements ValidateValue. A nil ctx is never checked.
+func validateValue(ctx context.Context, value any, limits Limits) error {
+	st := validatePool.Get().(*validateState)
+	defer st.release()
+
+	// Stack-based traversal to prevent recursion stack overflow
+	st.stack = append(st.stack, stackItem{value: value, depth: 0, seg: rootSegment})
 	totalNodes := 0
 
-	for len(stack) > 0 {
+	for len(st.stack) > 0 {
 		// Pop from stack
-		item := stack[len(stack)-1]
-		stack = stack[:len(stack)-1]
+		item := st.stack[len(st.stack)-1]
+		st.stack = st.stack[:len(st.stack)-1]
 
 		totalNodes++
+		if ctx != nil && totalNodes%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
 		if totalNodes > limits.MaxTotalNodes {
 			return &ValidationError{
 				Code:    ErrCodeTotalNodesTooLarge,
@@ -160,7 +223,7 @@ func ValidateValue(value any, limits Limits) error {
 			return &ValidationError{
 				Code:    ErrCodeDepthExceeded,
 				Message: fmt.Sprintf("depth (%d) exceeds limit (%d)", item.depth, limits.MaxDepth),
-				Path:    item.path,
+				Path:    st.path(item.seg),
 			}
 		}
 
@@ -179,14 +242,14 @@ func ValidateValue(value any, limits Limits) error {
 				return &ValidationError{
 					Code:    ErrCodeNonFiniteNumber,
 					Message: "NaN is not allowed in evidence",
-					Path:    item.path,
+					Path:    st.path(item.seg),
 				}
 			}
 			if math.IsInf(v, 0) {
 				return &ValidationError{
 					Code:    ErrCodeNonFiniteNumber,
 					Message: "Infinity is not allowed in evidence",
-					Path:    item.path,
+					Path:    st.path(item.seg),
 				}
 			}
 
@@ -195,7 +258,7 @@ func ValidateValue(value any, limits Limits) error {
 				return &ValidationError{
 					Code:    ErrCodeStringTooLong,
 					Message: fmt.Sprintf("string length (%d) exceeds limit (%d)", len(v), limits.MaxStringLength),
-					Path:    item.path,
+					Path:    st.path(item.seg),
 				}
 			}
 
@@ -204,16 +267,15 @@ func ValidateValue(value any, limits Limits) error {
 				return &ValidationError{
 					Code:    ErrCodeArrayTooLarge,
 					Message: fmt.Sprintf("array length (%d) exceeds limit (%d)", len(v), limits.MaxArrayLength),
-					Path:    item.path,
+					Path:    st.path(item.seg),
 				}
 			}
 			// Push array elements to stack (in reverse for correct order)
 			for i := len(v) - 1; i >= 0; i-- {
-				elemPath := fmt.Sprintf("%s[%d]", item.path, i)
-				stack = append(stack, stackItem{
+				st.stack = append(st.stack, stackItem{
 					value: v[i],
 					depth: item.depth + 1,
-					path:  elemPath,
+					seg:   st.addSegment(pathSegment{parent: item.seg, index: i}),
 				})
 			}
 
@@ -222,38 +284,41 @@ func ValidateValue(value any, limits Limits) error {
 				return &ValidationError{
 					Code:    ErrCodeObjectTooLarge,
 					Message: fmt.Sprintf("object keys (%d) exceeds limit (%d)", len(v), limits.MaxObjectKeys),
-					Path:    item.path,
+					Path:    st.path(item.seg),
 				}
 			}
 
 			// Sort keys for deterministic traversal order
-			keys := make([]string, 0, len(v))
+			st.keys = st.keys[:0]
 			for key := range v {
-				keys = append(keys, key)
+				st.keys = append(st.keys, key)
 			}
-			sort.Strings(keys)
+			sort.Strings(st.keys)
 
 			// Push object values to stack (in reverse sorted order for correct processing)
-			for i := len(keys) - 1; i >= 0; i-- {
-				key := keys[i]
-				val := v[key]
+			for i := len(st.keys) - 1; i >= 0; i-- {
+				key := st.keys[i]
+
+				if key == "" && limits.RejectEmptyKeys {
+					return &ValidationError{
+						Code:    ErrCodeEmptyKey,
+						Message: "empty object key is not allowed",
+						Path:    st.path(item.seg),
+					}
+				}
 
 				// Check key length
 				if len(key) > limits.MaxStringLength {
 					return &ValidationError{
 						Code:    ErrCodeStringTooLong,
 						Message: fmt.Sprintf("key length (%d) exceeds limit (%d)", len(key), limits.MaxStringLength),
-						Path:    item.path,
+						Path:    st.path(item.seg),
 					}
 				}
-				keyPath := item.path + "." + key
-				if item.path == "" {
-					keyPath = key
-				}
-				stack = append(stack, stackItem{
-					value: val,
+				st.stack = append(st.stack, stackItem{
+					value: v[key],
 					depth: item.depth + 1,
-					path:  keyPath,
+					seg:   st.addSegment(pathSegment{parent: item.seg, key: key, isKey: true}),
 				})
 			}
 
@@ -262,7 +327,7 @@ func ValidateValue(value any, limits Limits) error {
 			return &ValidationError{
 				Code:    ErrCodeInvalidJSON,
 				Message: fmt.Sprintf("unexpected type: %T", v),
-				Path:    item.path,
+				Path:    st.path(item.seg),
 			}
 		}
 	}
@@ -270,6 +335,85 @@ func ValidateValue(value any, limits Limits) error {
 	return nil
 }
 
+// stackItem is a pending node in ValidateValue's traversal.
+type stackItem struct {
+	value any
+	depth int
+	seg   int // index into validateState.segs
+}
+
+// pathSegment is one step of a node's path, linked to its parent segment.
+// Paths are stored this way so the string is only built on error.
+type pathSegment struct {
+	parent int
+	key    string
+	index  int
+	isKey  bool
+}
+
+// rootSegment marks the root value, whose path is "".
+const rootSegment = -1
+
+// maxPooledItems caps the traversal buffers kept in the pool, so one huge
+// payload does not pin its buffers for the life of the process.
+const maxPooledItems = 1 << 16
+
+// validateState holds reusable traversal buffers for ValidateValue.
+type validateState struct {
+	stack []stackItem
+	segs  []pathSegment
+	keys  []string
+}
+
+var validatePool = sync.Pool{
+	New: func() any { return &validateState{} },
+}
+
+func (st *validateState) addSegment(seg pathSegment) int {
+	st.segs = append(st.segs, seg)
+	return len(st.segs) - 1
+}
+
+// path renders the path of segment i in the historical format:
+// "a.b[2].c", with no leading separator.
+func (st *validateState) path(i int) string {
+	if i == rootSegment {
+		return ""
+	}
+	var chain []int
+	for ; i != rootSegment; i = st.segs[i].parent {
+		chain = append(chain, i)
+	}
+	var b strings.Builder
+	for j := len(chain) - 1; j >= 0; j-- {
+		seg := st.segs[chain[j]]
+		if seg.isKey {
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(seg.key)
+		} else {
+			b.WriteByte('[')
+			b.WriteString(strconv.Itoa(seg.index))
+			b.WriteByte(']')
+		}
+	}
+	return b.String()
+}
+
+func (st *validateState) release() {
+	if cap(st.stack) > maxPooledItems || cap(st.segs) > maxPooledItems || cap(st.keys) > maxPooledItems {
+		return
+	}
+	clear(st.stack[:cap(st.stack)])
+	clear(st.segs[:cap(st.segs)])
+	clear(st.keys[:cap(st.keys)])
+	st.stack = st.stack[:0]
+	st.segs = st.segs[:0]
+	st.keys = st.keys[:0]
+	validatePool.Put(st)
+}
+
 // ValidateJSON is a convenience function that parses and validates JSON evidence.
 func ValidateJSON(data []byte) error {
 	return Validate(data, DefaultLimits())
@@ -1,7 +1,10 @@
 package evidence
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
 	"strings"
 	"testing"
@@ -107,6 +110,41 @@ func TestLimits_WithDefaults(t *testing.T) {
 	})
 }
 
+func TestLimits_MergeWith(t *testing.T) {
+	defaults := DefaultLimits()
+	base := Limits{MaxDepth: 8, MaxStringLength: 256}
+
+	t.Run("override wins over base", func(t *testing.T) {
+		result := Limits{MaxDepth: 4}.MergeWith(base)
+		if result.MaxDepth != 4 {
+			t.Errorf("MaxDepth = %d, want 4", result.MaxDepth)
+		}
+		if result.MaxStringLength != 256 {
+			t.Errorf("MaxStringLength = %d, want 256 (base)", result.MaxStringLength)
+		}
+	})
+
+	t.Run("unset in both falls back to built-in", func(t *testing.T) {
+		result := Limits{}.MergeWith(base)
+		if result.MaxDepth != 8 {
+			t.Errorf("MaxDepth = %d, want 8 (base)", result.MaxDepth)
+		}
+		if result.MaxBytes != defaults.MaxBytes {
+			t.Errorf("MaxBytes = %d, want %d (default)", result.MaxBytes, defaults.MaxBytes)
+		}
+		if result.MaxTotalNodes != defaults.MaxTotalNodes {
+			t.Errorf("MaxTotalNodes = %d, want %d (default)", result.MaxTotalNodes, defaults.MaxTotalNodes)
+		}
+	})
+
+	t.Run("negative override uses base", func(t *testing.T) {
+		result := Limits{MaxStringLength: -1}.MergeWith(base)
+		if result.MaxStringLength != 256 {
+			t.Errorf("MaxStringLength = %d, want 256 (base)", result.MaxStringLength)
+		}
+	})
+}
+
 func TestValidate_EmptyData(t *testing.T) {
 	err := Validate([]byte{}, DefaultLimits())
 	if err != nil {
@@ -386,6 +424,40 @@ func TestValidate_KeyTooLong(t *testing.T) {
 	}
 }
 
+func TestValidate_EmptyKey(t *testing.T) {
+	strict := DefaultLimits()
+	strict.RejectEmptyKeys = true
+
+	tests := []struct {
+		name     string
+		input    string
+		wantPath string
+	}{
+		{"top-level", `{"": 1, "a": 2}`, ""},
+		{"nested", `{"a": {"b": [{"": "x"}]}}`, "a.b[0]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate([]byte(tt.input), DefaultLimits()); err != nil {
+				t.Errorf("empty key should pass by default, got error: %v", err)
+			}
+
+			err := Validate([]byte(tt.input), strict)
+			ve, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("error should be *ValidationError, got %T (%v)", err, err)
+			}
+			if ve.Code != ErrCodeEmptyKey || ve.Path != tt.wantPath {
+				t.Errorf("error = %s at %q, want %s at %q", ve.Code, ve.Path, ErrCodeEmptyKey, tt.wantPath)
+			}
+		})
+	}
+
+	if !(Limits{}).MergeWith(strict).RejectEmptyKeys {
+		t.Error("MergeWith should keep RejectEmptyKeys from base")
+	}
+}
+
 func TestValidate_TotalNodesExceeded(t *testing.T) {
 	limits := Limits{
 		MaxBytes:        1048576,
@@ -445,6 +517,18 @@ func TestValidate_PathReporting(t *testing.T) {
 	if ve.Path != "items[2]" {
 		t.Errorf("path = %s, want items[2]", ve.Path)
 	}
+
+	// Test mixed nesting and a root-level array
+	for input, want := range map[string]string{
+		`{"a": [{"b": "ok"}, {"b": "toolong"}]}`: "a[1].b",
+		`[["ok", "toolong"]]`:                    "[0][1]",
+	} {
+		err = Validate([]byte(input), limits)
+		ve, ok := err.(*ValidationError)
+		if !ok || ve.Path != want {
+			t.Errorf("Validate(%s) = %v, want path %s", input, err, want)
+		}
+	}
 }
 
 func TestValidate_DeterministicPathOrder(t *testing.T) {
@@ -777,3 +861,66 @@ func BenchmarkValidate_DeepNesting(b *testing.B) {
 		_ = Validate(data, limits)
 	}
 }
+
+func BenchmarkValidateValue_LargeMap(b *testing.B) {
+	// 500 keys, each holding a small nested object: the shape Issue passes
+	// for extension maps, with nothing near a limit.
+	value := make(map[string]any, 500)
+	for i := 0; i < 500; i++ {
+		value[fmt.Sprintf("com.example/key-%03d", i)] = map[string]any{
+			"id":   float64(i),
+			"tags": []any{"a", "b", "c"},
+		}
+	}
+	limits := DefaultLimits()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = ValidateValue(value, limits)
+	}
+}
+
+func TestValidateValueContext(t *testing.T) {
+	large := make([]any, 5000)
+	for i := range large {
+		large[i] = map[string]any{"i": float64(i)}
+	}
+
+	if err := ValidateValueContext(context.Background(), large, DefaultLimits()); err != nil {
+		t.Errorf("ValidateValueContext() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := ValidateValueContext(ctx, large, DefaultLimits()); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled ctx error = %v, want context.Canceled", err)
+	}
+
+	// Canceled mid-walk: the periodic check stops traversal.
+	ctx, cancel = context.WithCancel(context.Background())
+	calls := 0
+	walkCtx := &cancelAfterCtx{Context: ctx, cancel: cancel, after: 2, calls: &calls}
+	if err := ValidateValueContext(walkCtx, large, DefaultLimits()); !errors.Is(err, context.Canceled) {
+		t.Errorf("mid-walk error = %v, want context.Canceled", err)
+	}
+	if calls < 2 {
+		t.Errorf("ctx.Err() checked %d times, want periodic checks", calls)
+	}
+}
+
+// cancelAfterCtx cancels itself on the after-th call to Err.
+type cancelAfterCtx struct {
+	context.Context
+	cancel context.CancelFunc
+	after  int
+	calls  *int
+}
+
+func (c *cancelAfterCtx) Err() error {
+	*c.calls++
+	if *c.calls == c.after {
+		c.cancel()
+	}
+	return c.Context.Err()
+}
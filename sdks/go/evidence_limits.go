@@ -0,0 +1,48 @@
+package peac
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/peacprotocol/peac/sdks/go/evidence"
+)
+
+// defaultEvidenceLimits is the package-level evidence limits baseline.
+// Guarded by defaultEvidenceLimitsMu so it can be set at startup while
+// issuance runs concurrently.
+var (
+	defaultEvidenceLimitsMu sync.RWMutex
+	defaultEvidenceLimits   = evidence.DefaultLimits()
+)
+
+// SetDefaultEvidenceLimits sets the package-level evidence limits applied by
+// Issue when IssueOptions.EvidenceLimits leaves a field unset.
+//
+// Precedence, per field: IssueOptions.EvidenceLimits > package default >
+// evidence.DefaultLimits(). Zero or negative fields in limits fall back to the
+// built-in defaults. Safe for concurrent use.
+func SetDefaultEvidenceLimits(limits evidence.Limits) {
+	limits = limits.WithDefaults()
+	defaultEvidenceLimitsMu.Lock()
+	defaultEvidenceLimits = limits
+	defaultEvidenceLimitsMu.Unlock()
+}
+
+// DefaultEvidenceLimits returns the package-level evidence limits baseline.
+func DefaultEvidenceLimits() evidence.Limits {
+	defaultEvidenceLimitsMu.RLock()
+	defer defaultEvidenceLimitsMu.RUnlock()
+	return defaultEvidenceLimits
+}
+
+// validateEvidence validates value against limits, bounded by timeout when
+// it is positive.
+func validateEvidence(value any, limits evidence.Limits, timeout time.Duration) error {
+	if timeout <= 0 {
+		return evidence.ValidateValue(value, limits)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return evidence.ValidateValueContext(ctx, value, limits)
+}
@@ -0,0 +1,49 @@
+package peac
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/peacprotocol/peac/sdks/go/evidence"
+)
+
+func TestSetDefaultEvidenceLimits_Precedence(t *testing.T) {
+	prev := DefaultEvidenceLimits()
+	t.Cleanup(func() { SetDefaultEvidenceLimits(prev) })
+
+	SetDefaultEvidenceLimits(evidence.Limits{MaxStringLength: 8})
+
+	key := testSigningKey(t)
+	opts := IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/test",
+		SigningKey: key,
+		Extensions: map[string]any{"note": strings.Repeat("x", 16)},
+	}
+
+	// Package default applies when the per-issue limits are unset.
+	if _, err := Issue(opts); err == nil {
+		t.Fatal("expected package default MaxStringLength to reject extension")
+	}
+
+	// Per-issue limits override the package default.
+	opts.EvidenceLimits = evidence.Limits{MaxStringLength: 32}
+	if _, err := Issue(opts); err != nil {
+		t.Fatalf("expected per-issue limits to win, got %v", err)
+	}
+}
+
+func TestSetDefaultEvidenceLimits_FillsBuiltins(t *testing.T) {
+	prev := DefaultEvidenceLimits()
+	t.Cleanup(func() { SetDefaultEvidenceLimits(prev) })
+
+	SetDefaultEvidenceLimits(evidence.Limits{MaxDepth: 4})
+	got := DefaultEvidenceLimits()
+	if got.MaxDepth != 4 {
+		t.Errorf("MaxDepth = %d, want 4", got.MaxDepth)
+	}
+	if got.MaxBytes != evidence.DefaultLimits().MaxBytes {
+		t.Errorf("MaxBytes = %d, want built-in default", got.MaxBytes)
+	}
+}
@@ -0,0 +1,133 @@
+package peac
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+)
+
+// Commerce environment discriminants (CommerceExtension.Env).
+const (
+	EnvLive = "live"
+	EnvTest = "test"
+)
+
+// CommerceExtension is the org.peacprotocol/commerce extension: payment
+// transaction evidence.
+type CommerceExtension struct {
+	// PaymentRail identifies the rail (e.g. "stripe", "x402", "lightning").
+	PaymentRail string `json:"payment_rail"`
+
+	// AmountMinor is the amount in the smallest currency unit, as a base-10
+	// integer string.
+	AmountMinor string `json:"amount_minor"`
+
+	// Currency is an ISO 4217 code or asset identifier.
+	Currency string `json:"currency"`
+
+	// Reference is the caller-assigned payment reference.
+	Reference string `json:"reference,omitempty"`
+
+	// Asset identifies a non-fiat asset (e.g. a token address). It
+	// defaults to Currency when omitted.
+	Asset string `json:"asset,omitempty"`
+
+	// Env is EnvLive or EnvTest. Issue writes EnvTest when it is omitted;
+	// it is empty on a record that does not carry it.
+	Env string `json:"env,omitempty"`
+
+	// Event is the commerce lifecycle phase (authorization, capture, ...).
+	Event string `json:"event,omitempty"`
+}
+
+// Commerce decodes the commerce extension, applying the default for an
+// omitted asset (see IssueOptions.OmitDefaults). An omitted env is left
+// empty. It returns nil, nil when the record has none.
+func (c *InteractionRecordClaims) Commerce() (*CommerceExtension, error) {
+	var ext CommerceExtension
+	ok, err := c.decodeExtension(CommerceExtensionKey, &ext)
+	if !ok || err != nil {
+		return nil, err
+	}
+	if ext.Asset == "" {
+		ext.Asset = ext.Currency
+	}
+	return &ext, nil
+}
+
+// validateExplicitEnv checks that a commerce extension, if present, sets env
+// to EnvLive or EnvTest.
+func validateExplicitEnv(ext map[string]any) error {
+	commerce, ok := ext[CommerceExtensionKey].(map[string]any)
+	if !ok {
+		return nil
+	}
+	switch env, _ := commerce["env"].(string); env {
+	case EnvLive, EnvTest:
+		return nil
+	case "":
+		return &IssueError{
+			Code:    ErrCodeInvalidEnv,
+			Message: "commerce env is required",
+			Field:   "Extensions." + CommerceExtensionKey + ".env",
+			Details: map[string]any{"expected": []string{EnvLive, EnvTest}},
+		}
+	default:
+		return &IssueError{
+			Code:    ErrCodeInvalidEnv,
+			Message: fmt.Sprintf("commerce env must be %s or %s, got %q", EnvLive, EnvTest, env),
+			Field:   "Extensions." + CommerceExtensionKey + ".env",
+			Details: map[string]any{"value": env, "expected": []string{EnvLive, EnvTest}},
+		}
+	}
+}
+
+// withDefaultEnv returns ext with env set to EnvTest on a commerce extension
+// that omits it. The caller's maps are not modified.
+func withDefaultEnv(ext map[string]any) map[string]any {
+	commerce, ok := ext[CommerceExtensionKey].(map[string]any)
+	if !ok {
+		return ext
+	}
+	if env, _ := commerce["env"].(string); env != "" {
+		return ext
+	}
+	filled := maps.Clone(commerce)
+	filled["env"] = EnvTest
+	out := maps.Clone(ext)
+	out[CommerceExtensionKey] = filled
+	return out
+}
+
+// omitCommerceDefaults returns ext without a commerce asset that equals its
+// currency. The caller's maps are not modified.
+func omitCommerceDefaults(ext map[string]any) map[string]any {
+	commerce, ok := ext[CommerceExtensionKey].(map[string]any)
+	if !ok {
+		return ext
+	}
+	trimmed := maps.Clone(commerce)
+	if asset, ok := trimmed["asset"].(string); ok && asset == trimmed["currency"] {
+		delete(trimmed, "asset")
+	}
+	out := maps.Clone(ext)
+	out[CommerceExtensionKey] = trimmed
+	return out
+}
+
+// decodeExtension decodes ext[key] into out. It reports false when the key is
+// absent.
+func (c *InteractionRecordClaims) decodeExtension(key string, out any) (bool, error) {
+	raw, ok := c.Ext[key]
+	if !ok {
+		return false, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return true, fmt.Errorf("%s: %w", key, err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return true, fmt.Errorf("%s: %w", key, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,42 @@
+package peac
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+)
+
+// Fingerprint returns a stable short key for a record: the base64url
+// (unpadded) SHA-256 of the JCS-canonical identifying claims iss, the
+// receipt ID (see ReceiptID), and iat, plus amount_minor and currency from
+// the commerce extension when it is present. The same logical record yields
+// the same fingerprint however its JWS was serialized, so webhook processors
+// can use it for dedup and indexing.
+//
+// It is a content hash, not a signature: it proves nothing about who issued
+// the record. Fingerprint only verified claims. It returns "" for nil claims.
+func Fingerprint(claims *InteractionRecordClaims) string {
+	if claims == nil {
+		return ""
+	}
+	fields := map[string]any{
+		"iss": claims.Iss,
+		"rid": claims.ReceiptID(),
+		"iat": claims.Iat,
+	}
+	if commerce, err := claims.Commerce(); err == nil && commerce != nil {
+		fields["amount_minor"] = commerce.AmountMinor
+		fields["currency"] = commerce.Currency
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return ""
+	}
+	canonical, err := Canonicalize(data)
+	if err != nil {
+		return ""
+	}
+	h := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
@@ -0,0 +1,55 @@
+package peac
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	base := func() *InteractionRecordClaims {
+		return &InteractionRecordClaims{
+			Iss: "https://example.com",
+			Rid: "r1",
+			Iat: 1700000000,
+			Ext: map[string]any{
+				CommerceExtensionKey: map[string]any{"payment_rail": "stripe", "amount_minor": "500", "currency": "USD"},
+			},
+		}
+	}
+
+	fp := Fingerprint(base())
+	if len(fp) != 43 {
+		t.Fatalf("Fingerprint() = %q, want 43 base64url chars", fp)
+	}
+
+	// Non-identifying claims do not affect the fingerprint.
+	same := base()
+	same.Sub = "user:1"
+	same.Pillars = []string{"commerce"}
+	same.Ext[CommerceExtensionKey].(map[string]any)["reference"] = "ref-1"
+	if got := Fingerprint(same); got != fp {
+		t.Errorf("non-identifying change: got %q, want %q", got, fp)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(c *InteractionRecordClaims)
+	}{
+		{"iss", func(c *InteractionRecordClaims) { c.Iss = "https://other.example" }},
+		{"rid", func(c *InteractionRecordClaims) { c.Rid = "r2" }},
+		{"iat", func(c *InteractionRecordClaims) { c.Iat++ }},
+		{"amount", func(c *InteractionRecordClaims) { c.Ext[CommerceExtensionKey].(map[string]any)["amount_minor"] = "501" }},
+		{"currency", func(c *InteractionRecordClaims) { c.Ext[CommerceExtensionKey].(map[string]any)["currency"] = "EUR" }},
+		{"no commerce", func(c *InteractionRecordClaims) { c.Ext = nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := base()
+			tt.mutate(c)
+			if got := Fingerprint(c); got == fp {
+				t.Errorf("Fingerprint() unchanged after %s change", tt.name)
+			}
+		})
+	}
+
+	if got := Fingerprint(nil); got != "" {
+		t.Errorf("Fingerprint(nil) = %q, want empty", got)
+	}
+}
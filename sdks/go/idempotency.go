@@ -0,0 +1,88 @@
+package peac
+
+import (
+	"sync"
+	"time"
+)
+
+// IdempotencyStore records issuance results by idempotency key so retried
+// requests get the original record back. Implementations must be safe for
+// concurrent use.
+//
+// Issue calls Get before signing and Put after, so two concurrent first
+// attempts with the same key can both issue; serialize retries upstream if
+// that matters.
+type IdempotencyStore interface {
+	Get(key string) (*IssueResult, bool)
+	Put(key string, result *IssueResult)
+}
+
+// DefaultIdempotencyTTL is how long MemoryIdempotencyStore keeps results when
+// no TTL is given.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// MemoryIdempotencyStore is an in-memory IdempotencyStore whose entries
+// expire after a TTL. Expired entries are evicted on access and by Prune.
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]idempotencyEntry
+	ttl     time.Duration
+	clock   Clock
+}
+
+type idempotencyEntry struct {
+	result    *IssueResult
+	expiresAt time.Time
+}
+
+// NewMemoryIdempotencyStore creates an empty store. If ttl is zero,
+// DefaultIdempotencyTTL is used. If clock is nil, the default clock is used.
+func NewMemoryIdempotencyStore(ttl time.Duration, clock Clock) *MemoryIdempotencyStore {
+	if ttl == 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	if clock == nil {
+		clock = DefaultClock()
+	}
+	return &MemoryIdempotencyStore{
+		entries: make(map[string]idempotencyEntry),
+		ttl:     ttl,
+		clock:   clock,
+	}
+}
+
+// Get returns the unexpired result stored for key.
+func (s *MemoryIdempotencyStore) Get(key string) (*IssueResult, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !s.clock.Now().Before(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, false
+	}
+	return entry.result, true
+}
+
+// Put stores result for key for the store's TTL.
+func (s *MemoryIdempotencyStore) Put(key string, result *IssueResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = idempotencyEntry{result: result, expiresAt: s.clock.Now().Add(s.ttl)}
+}
+
+// Prune removes expired entries.
+func (s *MemoryIdempotencyStore) Prune() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	for key, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
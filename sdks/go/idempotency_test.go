@@ -0,0 +1,67 @@
+package peac
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIssue_IdempotencyStore(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	clock := &FixedClock{Time: now}
+	store := NewMemoryIdempotencyStore(time.Hour, clock)
+	opts := IssueOptions{
+		Iss:              "https://example.com",
+		Kind:             KindEvidence,
+		Type:             "org.peacprotocol/test",
+		SigningKey:       testSigningKey(t),
+		IdempotencyKey:   "order-42",
+		IdempotencyStore: store,
+	}
+
+	first, err := Issue(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	retry, err := Issue(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retry.ReceiptID != first.ReceiptID || retry.JWS != first.JWS {
+		t.Error("retry with same key should return the original record")
+	}
+
+	other := opts
+	other.IdempotencyKey = "order-43"
+	fresh, err := Issue(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fresh.ReceiptID == first.ReceiptID {
+		t.Error("different key should issue a new record")
+	}
+
+	// After the TTL the key issues again.
+	clock.Time = now.Add(time.Hour)
+	expired, err := Issue(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expired.ReceiptID == first.ReceiptID {
+		t.Error("expired key should issue a new record")
+	}
+}
+
+func TestIssue_IdempotencyKeyWithoutStore(t *testing.T) {
+	opts := IssueOptions{
+		Iss:            "https://example.com",
+		Kind:           KindEvidence,
+		Type:           "org.peacprotocol/test",
+		SigningKey:     testSigningKey(t),
+		IdempotencyKey: "order-42",
+	}
+	a, _ := Issue(opts)
+	b, _ := Issue(opts)
+	if a.ReceiptID == b.ReceiptID {
+		t.Error("without a store every call should issue a fresh record")
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"sync"
 	"time"
 )
 
 // ReceiptIDGenerator generates unique receipt IDs.
-// Use UUIDv7Generator for production and FixedIDGenerator for testing.
+// Use UUIDv7Generator for production and FixedIDGenerator or
+// SequentialIDGenerator for testing.
 type ReceiptIDGenerator interface {
 	// NewReceiptID generates a new unique receipt ID.
 	// Returns an error if ID generation fails (e.g., crypto/rand failure).
@@ -20,6 +22,7 @@ type ReceiptIDGenerator interface {
 // UUID v7 is timestamp-ordered, making receipts sortable by issuance time.
 type UUIDv7Generator struct {
 	clock Clock
+	rand  io.Reader
 }
 
 // NewUUIDv7Generator creates a generator using the given clock.
@@ -31,10 +34,21 @@ func NewUUIDv7Generator(clock Clock) *UUIDv7Generator {
 	return &UUIDv7Generator{clock: clock}
 }
 
+// NewUUIDv7GeneratorWithRand creates a generator using the given clock and
+// random source. If clock is nil, RealClock is used; if rand is nil,
+// crypto/rand.Reader is used. Pair a FixedClock with a deterministic reader
+// for reproducible IDs in golden-vector tests. The reader must be safe for
+// concurrent use if the generator is shared.
+func NewUUIDv7GeneratorWithRand(clock Clock, rand io.Reader) *UUIDv7Generator {
+	g := NewUUIDv7Generator(clock)
+	g.rand = rand
+	return g
+}
+
 // NewReceiptID generates a new UUID v7.
 // Returns an error if random number generation fails.
 func (g *UUIDv7Generator) NewReceiptID() (string, error) {
-	return uuidv7(g.clock.Now())
+	return uuidv7(g.clock.Now(), g.rand)
 }
 
 // FixedIDGenerator returns IDs from a predefined list.
@@ -64,6 +78,55 @@ func (g *FixedIDGenerator) NewReceiptID() (string, error) {
 	return id, nil
 }
 
+// SequentialIDGenerator returns prefix-numbered IDs ("<prefix>-001",
+// "<prefix>-002", ...). Use this for deterministic testing where IDs must be
+// distinct but predictable; Reset and SetCounter make them reproducible
+// across subtests without building a new generator.
+type SequentialIDGenerator struct {
+	mu      sync.Mutex
+	prefix  string
+	counter int
+}
+
+// NewSequentialIDGenerator creates a generator whose first ID is
+// "<prefix>-001". If prefix is empty, "test-receipt-id" is used.
+func NewSequentialIDGenerator(prefix string) *SequentialIDGenerator {
+	if prefix == "" {
+		prefix = "test-receipt-id"
+	}
+	return &SequentialIDGenerator{prefix: prefix}
+}
+
+// NewReceiptID returns the next ID in the sequence.
+// This never fails.
+func (g *SequentialIDGenerator) NewReceiptID() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.counter++
+	return fmt.Sprintf("%s-%03d", g.prefix, g.counter), nil
+}
+
+// Reset restarts the sequence so the next ID is "<prefix>-001".
+func (g *SequentialIDGenerator) Reset() {
+	g.SetCounter(0)
+}
+
+// SetCounter sets the number of IDs already issued; the next ID is n+1.
+func (g *SequentialIDGenerator) SetCounter(n int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.counter = n
+}
+
+// Rotate replaces the prefix and counter in one step, so concurrent callers
+// never observe the new prefix with the old counter or vice versa.
+func (g *SequentialIDGenerator) Rotate(prefix string, n int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.prefix = prefix
+	g.counter = n
+}
+
 // defaultIDGenerator is the package-level default generator.
 var defaultIDGenerator ReceiptIDGenerator = NewUUIDv7Generator(nil)
 
@@ -72,10 +135,15 @@ func DefaultIDGenerator() ReceiptIDGenerator {
 	return defaultIDGenerator
 }
 
-// uuidv7 generates a UUID v7 string for the given timestamp.
+// uuidv7 generates a UUID v7 string for the given timestamp, reading the
+// random bits from r (crypto/rand.Reader if nil).
 // Format: xxxxxxxx-xxxx-7xxx-yxxx-xxxxxxxxxxxx
 // where x is timestamp/random and y is variant (8, 9, a, or b).
-func uuidv7(t time.Time) (string, error) {
+func uuidv7(t time.Time, r io.Reader) (string, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+
 	var uuid [16]byte
 
 	// Timestamp: milliseconds since Unix epoch (48 bits)
@@ -88,7 +156,7 @@ func uuidv7(t time.Time) (string, error) {
 	uuid[5] = byte(ms)
 
 	// Random bytes for the rest
-	if _, err := rand.Read(uuid[6:]); err != nil {
+	if _, err := io.ReadFull(r, uuid[6:]); err != nil {
 		return "", fmt.Errorf("failed to generate random bytes: %w", err)
 	}
 
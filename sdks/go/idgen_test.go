@@ -1,6 +1,7 @@
 package peac
 
 import (
+	"bytes"
 	"regexp"
 	"testing"
 	"time"
@@ -65,6 +66,27 @@ func TestUUIDv7Generator_WithFixedClock(t *testing.T) {
 	}
 }
 
+func TestUUIDv7Generator_WithRand(t *testing.T) {
+	clock := FixedClock{Time: time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)}
+	gen := NewUUIDv7GeneratorWithRand(clock, bytes.NewReader(bytes.Repeat([]byte{0xff}, 10)))
+
+	id, err := gen.NewReceiptID()
+	if err != nil {
+		t.Fatalf("NewReceiptID() error = %v", err)
+	}
+
+	// Version and variant bits are set over the all-ones random bytes
+	want := "019469d5-b200-7fff-bfff-ffffffffffff"
+	if id != want {
+		t.Errorf("NewReceiptID() = %q, want %q", id, want)
+	}
+
+	// Exhausted reader surfaces an error rather than a short ID
+	if _, err := gen.NewReceiptID(); err == nil {
+		t.Error("NewReceiptID() with exhausted reader should fail")
+	}
+}
+
 func TestFixedIDGenerator_NewReceiptID(t *testing.T) {
 	gen := NewFixedIDGenerator("id-001", "id-002", "id-003")
 
@@ -153,6 +175,65 @@ func TestFixedIDGenerator_Concurrent(t *testing.T) {
 	}
 }
 
+func TestSequentialIDGenerator(t *testing.T) {
+	gen := NewSequentialIDGenerator("rid")
+
+	next := func() string {
+		t.Helper()
+		id, err := gen.NewReceiptID()
+		if err != nil {
+			t.Fatalf("NewReceiptID() error = %v", err)
+		}
+		return id
+	}
+
+	if got := next(); got != "rid-001" {
+		t.Errorf("first ID = %q, want rid-001", got)
+	}
+	if got := next(); got != "rid-002" {
+		t.Errorf("second ID = %q, want rid-002", got)
+	}
+
+	gen.Reset()
+	if got := next(); got != "rid-001" {
+		t.Errorf("after Reset() = %q, want rid-001", got)
+	}
+
+	gen.SetCounter(41)
+	if got := next(); got != "rid-042" {
+		t.Errorf("after SetCounter(41) = %q, want rid-042", got)
+	}
+
+	gen.Rotate("case-b", 0)
+	if got := next(); got != "case-b-001" {
+		t.Errorf("after Rotate() = %q, want case-b-001", got)
+	}
+}
+
+func TestSequentialIDGenerator_Concurrent(t *testing.T) {
+	gen := NewSequentialIDGenerator("")
+
+	ids := make(chan string, 100)
+	for i := 0; i < 100; i++ {
+		go func() {
+			id, _ := gen.NewReceiptID()
+			ids <- id
+		}()
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id := <-ids
+		if seen[id] {
+			t.Errorf("duplicate ID %q", id)
+		}
+		seen[id] = true
+	}
+	if !seen["test-receipt-id-100"] {
+		t.Error("expected test-receipt-id-100 to be issued")
+	}
+}
+
 func TestDefaultIDGenerator(t *testing.T) {
 	gen := DefaultIDGenerator()
 	if _, ok := gen.(*UUIDv7Generator); !ok {
@@ -173,12 +254,13 @@ func TestReceiptIDGenerator_Interface(t *testing.T) {
 	// Verify all generator types implement the interface
 	var _ ReceiptIDGenerator = &UUIDv7Generator{}
 	var _ ReceiptIDGenerator = &FixedIDGenerator{}
+	var _ ReceiptIDGenerator = &SequentialIDGenerator{}
 }
 
 func TestUUIDv7_Format(t *testing.T) {
 	// Test specific timestamp encoding
 	ts := time.Date(2025, 1, 15, 12, 30, 45, 0, time.UTC)
-	id, err := uuidv7(ts)
+	id, err := uuidv7(ts, nil)
 	if err != nil {
 		t.Fatalf("uuidv7() error = %v", err)
 	}
@@ -102,8 +102,7 @@ func (s *ijsonScanner) parseValue() error {
 	case c == '[':
 		return s.parseArray()
 	case c == '"':
-		_, err := s.parseString()
-		return err
+		return s.parseString(nil)
 	case c == 't':
 		return s.parseLiteral("true")
 	case c == 'f':
@@ -140,10 +139,11 @@ func (s *ijsonScanner) parseObject() error {
 		if s.i >= len(s.b) || s.b[s.i] != '"' {
 			return s.syntax("expected string member name")
 		}
-		name, err := s.parseString()
-		if err != nil {
+		var decoded decodedString
+		if err := s.parseString(&decoded); err != nil {
 			return err
 		}
+		name := decoded.String()
 		if _, dup := names[name]; dup {
 			return &ijsonError{
 				Code: "E_IJSON_DUPLICATE_MEMBER_NAME",
@@ -202,12 +202,34 @@ func (s *ijsonScanner) parseArray() error {
 	}
 }
 
+// decodedString accumulates the value decoded by parseString. A nil
+// *decodedString discards it, so string values are validated without
+// allocating; only member names need their decoded form.
+type decodedString struct{ strings.Builder }
+
+func (d *decodedString) write(p []byte) {
+	if d != nil {
+		d.Write(p)
+	}
+}
+
+func (d *decodedString) writeByte(c byte) {
+	if d != nil {
+		d.WriteByte(c)
+	}
+}
+
+func (d *decodedString) writeRune(r rune) {
+	if d != nil {
+		d.WriteRune(r)
+	}
+}
+
 // parseString parses a JSON string from raw bytes: validates escapes + surrogate
-// pairing + UTF-8 of raw runs, and returns the decoded value (used for
+// pairing + UTF-8 of raw runs, and writes the decoded value to out (used for
 // duplicate-member-name comparison after escape processing per RFC 7493).
-func (s *ijsonScanner) parseString() (string, error) {
+func (s *ijsonScanner) parseString(out *decodedString) error {
 	s.i++ // consume opening '"'
-	var out strings.Builder
 	runStart := s.i
 	flushRun := func(end int) error {
 		if end > runStart {
@@ -221,95 +243,95 @@ func (s *ijsonScanner) parseString() (string, error) {
 					return s.invalidString("noncharacter in string")
 				}
 			}
-			out.Write(run)
+			out.write(run)
 		}
 		return nil
 	}
 	for {
 		if s.atEnd() {
-			return "", s.invalidString("unterminated string")
+			return s.invalidString("unterminated string")
 		}
 		c := s.b[s.i]
 		if c == '"' {
 			if err := flushRun(s.i); err != nil {
-				return "", err
+				return err
 			}
 			s.i++
-			return out.String(), nil
+			return nil
 		}
 		if c == '\\' {
 			if err := flushRun(s.i); err != nil {
-				return "", err
+				return err
 			}
 			s.i++ // consume '\'
 			if s.atEnd() {
-				return "", s.invalidString("unterminated escape sequence")
+				return s.invalidString("unterminated escape sequence")
 			}
 			e := s.b[s.i]
 			switch e {
 			case '"':
-				out.WriteByte('"')
+				out.writeByte('"')
 				s.i++
 			case '\\':
-				out.WriteByte('\\')
+				out.writeByte('\\')
 				s.i++
 			case '/':
-				out.WriteByte('/')
+				out.writeByte('/')
 				s.i++
 			case 'b':
-				out.WriteByte('\b')
+				out.writeByte('\b')
 				s.i++
 			case 'f':
-				out.WriteByte('\f')
+				out.writeByte('\f')
 				s.i++
 			case 'n':
-				out.WriteByte('\n')
+				out.writeByte('\n')
 				s.i++
 			case 'r':
-				out.WriteByte('\r')
+				out.writeByte('\r')
 				s.i++
 			case 't':
-				out.WriteByte('\t')
+				out.writeByte('\t')
 				s.i++
 			case 'u':
 				s.i++
 				hi, err := s.readHex4()
 				if err != nil {
-					return "", err
+					return err
 				}
 				if hi >= 0xd800 && hi <= 0xdbff {
 					if s.i+1 >= len(s.b) || s.b[s.i] != '\\' || s.b[s.i+1] != 'u' {
-						return "", s.invalidString("lone high surrogate in \\u escape")
+						return s.invalidString("lone high surrogate in \\u escape")
 					}
 					s.i += 2 // consume "\u" of low surrogate
 					lo, err := s.readHex4()
 					if err != nil {
-						return "", err
+						return err
 					}
 					if lo < 0xdc00 || lo > 0xdfff {
-						return "", s.invalidString("high surrogate not followed by a low surrogate")
+						return s.invalidString("high surrogate not followed by a low surrogate")
 					}
 					r := 0x10000 + (rune(hi-0xd800) << 10) + rune(lo-0xdc00)
 					if isUnicodeNoncharacter(r) {
-						return "", s.invalidString("noncharacter in string")
+						return s.invalidString("noncharacter in string")
 					}
-					out.WriteRune(r)
+					out.writeRune(r)
 				} else if hi >= 0xdc00 && hi <= 0xdfff {
-					return "", s.invalidString("lone low surrogate in \\u escape")
+					return s.invalidString("lone low surrogate in \\u escape")
 				} else {
 					if isUnicodeNoncharacter(rune(hi)) {
-						return "", s.invalidString("noncharacter in string")
+						return s.invalidString("noncharacter in string")
 					}
-					out.WriteRune(rune(hi))
+					out.writeRune(rune(hi))
 				}
 			default:
-				return "", s.invalidString("invalid escape sequence")
+				return s.invalidString("invalid escape sequence")
 			}
 			runStart = s.i
 			continue
 		}
 		if c < 0x20 {
-			return "", s.syntax("unescaped control character in string")
+			return s.syntax("unescaped control character in string")
 		}
 		// Normal byte (printable ASCII, or part of a multibyte UTF-8 sequence
 		// validated together by flushRun via utf8.Valid).
@@ -2,8 +2,11 @@
 package peac
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -37,6 +40,10 @@ type IssueOptions struct {
 	// Pillars is the optional list of pillar values from the 10-pillar taxonomy.
 	Pillars []string
 
+	// PurposeDeclared is the optional declared purpose of the interaction,
+	// at most MaxPurposeDeclaredLength bytes.
+	PurposeDeclared string
+
 	// Actor is the optional top-level actor binding.
 	Actor *ActorBinding
 
@@ -52,8 +59,53 @@ type IssueOptions struct {
 	// IDGen for receipt ID generation (optional; uses UUIDv7 if nil).
 	IDGen ReceiptIDGenerator
 
-	// EvidenceLimits for DoS protection on extension values (optional; uses defaults if zero).
+	// EvidenceLimits for DoS protection on extension values (optional).
+	// Unset fields fall back to DefaultEvidenceLimits(), then to
+	// evidence.DefaultLimits().
 	EvidenceLimits evidence.Limits
+
+	// EvidenceTimeout caps the time spent validating Extensions against
+	// EvidenceLimits (optional). Exceeding it fails with
+	// ErrCodeEvidenceTimeout, the code VerifyLocal reports for the same
+	// condition. Zero means no deadline.
+	EvidenceTimeout time.Duration
+
+	// IdempotencyKey identifies a logical issuance (optional). With
+	// IdempotencyStore set, a retry with the same key returns the stored
+	// result instead of minting a new record. It is not written into the
+	// record.
+	IdempotencyKey string
+
+	// IdempotencyStore deduplicates issuance by IdempotencyKey (optional).
+	// Without a store, every call issues a fresh record.
+	IdempotencyStore IdempotencyStore
+
+	// MaxReceiptBytes caps the length of the signed compact JWS (optional).
+	// EvidenceLimits bound the extensions alone; this bounds what goes on
+	// the wire, after base64 expansion of the header and claims, so a
+	// record that would not fit in an HTTP header fails with
+	// ErrCodeReceiptTooLarge instead of being issued. Zero means no limit.
+	MaxReceiptBytes int
+
+	// OmitDefaults drops the commerce asset when it equals currency. This
+	// trims bytes from every payment record for high-volume issuers; the
+	// cost is that the signed payload no longer states the asset
+	// explicitly, so verifiers must apply the default on read, as
+	// InteractionRecordClaims.Commerce does. Env is always written. Off by
+	// default.
+	OmitDefaults bool
+
+	// StrictEnv requires a commerce extension to state its env explicitly,
+	// as EnvLive or EnvTest, failing with ErrCodeInvalidEnv otherwise
+	// instead of writing EnvTest for an omitted env. Safety-critical
+	// issuers should set it so a forgotten env never mints test records.
+	// Records without a commerce extension are unaffected.
+	StrictEnv bool
+
+	// StandardClaims also emits the receipt ID as the RFC 7519 jti claim,
+	// for JWT tooling and issuers that read jti rather than rid. The sub
+	// claim is always emitted from Sub when set.
+	StandardClaims bool
 }
 
 // IssueResult contains the output of a successful Issue() call.
@@ -66,6 +118,10 @@ type IssueResult struct {
 
 	// IssuedAt is the Unix timestamp (seconds) when the record was issued.
 	IssuedAt int64
+
+	// Claims are the exact claims that were signed, so callers can log or
+	// store them without re-parsing JWS.
+	Claims *InteractionRecordClaims
 }
 
 // IssueError represents a structured issuance error with a code and field path.
@@ -73,6 +129,11 @@ type IssueError struct {
 	Code    string
 	Message string
 	Field   string
+
+	// Details carries machine-readable diagnostics for validation failures,
+	// such as "value" (the offending input) and "expected" (the accepted
+	// values). Nil when there is nothing beyond the message.
+	Details map[string]any
 }
 
 func (e *IssueError) Error() string {
@@ -82,6 +143,14 @@ func (e *IssueError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Code, e.Message)
 }
 
+// Is reports whether target is an *IssueError with the same Code, so
+// errors.Is(err, &IssueError{Code: ErrCodeInvalidKind}) matches any invalid
+// kind error regardless of message or details.
+func (e *IssueError) Is(target error) bool {
+	t, ok := target.(*IssueError)
+	return ok && t.Code == e.Code
+}
+
 // validateCanonicalIss checks that iss starts with https:// or did: per the
 // Interaction Record spec.
 func validateCanonicalIss(iss string) error {
@@ -100,30 +169,101 @@ func validateCanonicalIss(iss string) error {
 	return fmt.Errorf("%w: got %q", ErrIssNotCanonical, iss)
 }
 
-// Issue creates a signed interaction record in the current stable format
-// (interaction-record+jwt).
+// ValidateIssueOptions runs every input validation step performed by Issue
+// (issuer, kind, type, pillars, purpose, extensions, registered rail evidence) without
+// requiring a signing key and without signing. Use it to check issuance
+// configuration early, before a key is available.
 //
-// Validates all inputs, generates a UUIDv7 receipt ID, and signs with Ed25519.
-func Issue(opts IssueOptions) (*IssueResult, error) {
-	// Validate required fields
+// Returns the same *IssueError that Issue would return for the same input.
+func ValidateIssueOptions(opts IssueOptions) error {
 	if opts.Iss == "" {
-		return nil, &IssueError{Code: ErrCodeMissingIssuer, Message: "iss is required", Field: "Iss"}
+		return &IssueError{Code: ErrCodeMissingIssuer, Message: "iss is required", Field: "Iss"}
 	}
 	if err := validateCanonicalIss(opts.Iss); err != nil {
-		return nil, &IssueError{Code: ErrCodeInvalidIss, Message: err.Error(), Field: "Iss"}
+		return &IssueError{Code: ErrCodeInvalidIss, Message: err.Error(), Field: "Iss", Details: map[string]any{"value": opts.Iss}}
 	}
 
 	if opts.Kind == "" {
-		return nil, &IssueError{Code: ErrCodeMissingKind, Message: "kind is required", Field: "Kind"}
+		return &IssueError{Code: ErrCodeMissingKind, Message: "kind is required", Field: "Kind"}
 	}
 	if !ValidKinds[opts.Kind] {
-		return nil, &IssueError{Code: ErrCodeInvalidKind, Message: fmt.Sprintf("kind must be evidence or challenge, got %q", opts.Kind), Field: "Kind"}
+		return &IssueError{
+			Code:    ErrCodeInvalidKind,
+			Message: fmt.Sprintf("kind must be evidence or challenge, got %q", opts.Kind),
+			Field:   "Kind",
+			Details: map[string]any{"value": opts.Kind, "expected": []string{KindEvidence, KindChallenge}},
+		}
 	}
 
 	if opts.Type == "" {
-		return nil, &IssueError{Code: ErrCodeMissingType, Message: "type is required", Field: "Type"}
+		return &IssueError{Code: ErrCodeMissingType, Message: "type is required", Field: "Type"}
+	}
+
+	// Validate pillars if provided
+	for _, p := range opts.Pillars {
+		if !ValidPillars[p] {
+			return &IssueError{Code: ErrCodeInvalidPillar, Message: fmt.Sprintf("invalid pillar %q", p), Field: "Pillars", Details: map[string]any{"value": p}}
+		}
+	}
+
+	if len(opts.PurposeDeclared) > MaxPurposeDeclaredLength {
+		return &IssueError{
+			Code:    ErrCodeInvalidPurpose,
+			Message: fmt.Sprintf("purpose_declared exceeds %d bytes", MaxPurposeDeclaredLength),
+			Field:   "PurposeDeclared",
+		}
+	}
+
+	// Validate extensions if provided
+	if opts.Extensions != nil {
+		limits := opts.EvidenceLimits.MergeWith(DefaultEvidenceLimits())
+		if err := validateEvidence(opts.Extensions, limits, opts.EvidenceTimeout); err != nil {
+			code := ErrCodeInvalidType
+			if errors.Is(err, context.DeadlineExceeded) {
+				code = ErrCodeEvidenceTimeout
+			}
+			return &IssueError{Code: code, Message: fmt.Sprintf("extension validation failed: %v", err), Field: "Extensions"}
+		}
+		if err := validateRailEvidence(opts.Extensions); err != nil {
+			return err
+		}
+		if opts.StrictEnv {
+			if err := validateExplicitEnv(opts.Extensions); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Issue creates a signed interaction record in the current stable format
+// (interaction-record+jwt).
+//
+// Validates all inputs, generates a UUIDv7 receipt ID, and signs with Ed25519.
+// When IdempotencyKey and IdempotencyStore are both set, a previously stored
+// result for the key is returned as-is.
+func Issue(opts IssueOptions) (*IssueResult, error) {
+	if err := ValidateIssueOptions(opts); err != nil {
+		return nil, err
 	}
 
+	if opts.IdempotencyStore == nil || opts.IdempotencyKey == "" {
+		return issue(opts)
+	}
+	if prior, ok := opts.IdempotencyStore.Get(opts.IdempotencyKey); ok {
+		return prior, nil
+	}
+	result, err := issue(opts)
+	if err != nil {
+		return nil, err
+	}
+	opts.IdempotencyStore.Put(opts.IdempotencyKey, result)
+	return result, nil
+}
+
+// issue signs a record from already-validated options.
+func issue(opts IssueOptions) (*IssueResult, error) {
 	if opts.SigningKey == nil {
 		return nil, &IssueError{Code: ErrCodeMissingKey, Message: "signing key is required", Field: "SigningKey"}
 	}
@@ -136,21 +276,6 @@ func Issue(opts IssueOptions) (*IssueResult, error) {
 		return nil, &IssueError{Code: ErrCodeMissingKid, Message: "kid is required", Field: "Kid"}
 	}
 
-	// Validate pillars if provided
-	for _, p := range opts.Pillars {
-		if !ValidPillars[p] {
-			return nil, &IssueError{Code: ErrCodeInvalidPillar, Message: fmt.Sprintf("invalid pillar %q", p), Field: "Pillars"}
-		}
-	}
-
-	// Validate extensions if provided
-	if opts.Extensions != nil {
-		limits := opts.EvidenceLimits.WithDefaults()
-		if err := evidence.ValidateValue(opts.Extensions, limits); err != nil {
-			return nil, &IssueError{Code: ErrCodeInvalidType, Message: fmt.Sprintf("extension validation failed: %v", err), Field: "Extensions"}
-		}
-	}
-
 	// Clock and ID generator
 	clock := opts.Clock
 	if clock == nil {
@@ -170,21 +295,28 @@ func Issue(opts IssueOptions) (*IssueResult, error) {
 
 	// Build claims
 	claims := InteractionRecordClaims{
-		Iss:         opts.Iss,
-		Sub:         opts.Sub,
-		Iat:         issuedAt,
-		Rid:         receiptID,
-		Kind:        opts.Kind,
-		Type:        opts.Type,
-		PeacVersion: PeacVersion,
-		Pillars:     opts.Pillars,
-		Actor:       opts.Actor,
-		Ext:         opts.Extensions,
-		Peac:        opts.Policy,
+		Iss:             opts.Iss,
+		Sub:             opts.Sub,
+		Iat:             issuedAt,
+		Rid:             receiptID,
+		Kind:            opts.Kind,
+		Type:            opts.Type,
+		PeacVersion:     PeacVersion,
+		Pillars:         opts.Pillars,
+		PurposeDeclared: opts.PurposeDeclared,
+		Actor:           opts.Actor,
+		Ext:             withDefaultEnv(opts.Extensions),
+		Peac:            opts.Policy,
 	}
 	if opts.Exp > 0 {
 		claims.Exp = opts.Exp
 	}
+	if opts.StandardClaims {
+		claims.Jti = receiptID
+	}
+	if opts.OmitDefaults {
+		claims.Ext = omitCommerceDefaults(claims.Ext)
+	}
 
 	// Marshal and sign
 	payload, err := json.Marshal(claims)
@@ -196,11 +328,20 @@ func Issue(opts IssueOptions) (*IssueResult, error) {
 	if err != nil {
 		return nil, &IssueError{Code: ErrCodeSignFailed, Message: fmt.Sprintf("failed to sign: %v", err)}
 	}
+	if opts.MaxReceiptBytes > 0 && len(jwsString) > opts.MaxReceiptBytes {
+		return nil, &IssueError{
+			Code:    ErrCodeReceiptTooLarge,
+			Message: fmt.Sprintf("signed receipt is %d bytes, limit is %d", len(jwsString), opts.MaxReceiptBytes),
+			Field:   "MaxReceiptBytes",
+			Details: map[string]any{"size": len(jwsString), "limit": opts.MaxReceiptBytes},
+		}
+	}
 
 	return &IssueResult{
 		JWS:       jwsString,
 		ReceiptID: receiptID,
 		IssuedAt:  issuedAt,
+		Claims:    &claims,
 	}, nil
 }
 
@@ -222,3 +363,61 @@ func DefaultIssueOptions() IssueOptions {
 		Clock: FixedClock{Time: time.Now()},
 	}
 }
+
+// With returns a copy of o with mods applied in order, for issuing many
+// records from one template without field bleed between them. Extensions
+// is deep-copied (nested map[string]any and []any values included), as are
+// Pillars, Actor, and Policy, so a mod or a later Issue never writes
+// through to o. SigningKey, Clock, IDGen, and IdempotencyStore are shared,
+// since they are meant to be reused.
+func (o IssueOptions) With(mods ...func(*IssueOptions)) IssueOptions {
+	c := o
+	c.Extensions = cloneExtensions(o.Extensions)
+	c.Pillars = slices.Clone(o.Pillars)
+	if o.Actor != nil {
+		actor := *o.Actor
+		actor.ProofTypes = slices.Clone(o.Actor.ProofTypes)
+		c.Actor = &actor
+	}
+	if o.Policy != nil {
+		policy := *o.Policy
+		c.Policy = &policy
+	}
+	for _, mod := range mods {
+		mod(&c)
+	}
+	return c
+}
+
+// cloneExtensions deep-copies an extension map. Values other than maps and
+// slices of JSON values are copied as-is.
+func cloneExtensions(ext map[string]any) map[string]any {
+	if ext == nil {
+		return nil
+	}
+	out := make(map[string]any, len(ext))
+	for k, v := range ext {
+		out[k] = cloneJSONValue(v)
+	}
+	return out
+}
+
+func cloneJSONValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		return cloneExtensions(v)
+	case []any:
+		if v == nil {
+			return v
+		}
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = cloneJSONValue(e)
+		}
+		return out
+	case []string:
+		return slices.Clone(v)
+	default:
+		return v
+	}
+}
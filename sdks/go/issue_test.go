@@ -2,6 +2,7 @@ package peac
 
 import (
 	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -162,6 +163,40 @@ func TestIssue_RejectsInvalidKind(t *testing.T) {
 	}
 }
 
+func TestIssueError_DetailsAndIs(t *testing.T) {
+	key := testSigningKey(t)
+	_, err := Issue(IssueOptions{Iss: "https://example.com", Kind: "unknown", Type: "org.peacprotocol/test", SigningKey: key})
+
+	if !errors.Is(err, &IssueError{Code: ErrCodeInvalidKind}) {
+		t.Errorf("errors.Is(%v, INVALID_KIND) = false", err)
+	}
+	if errors.Is(err, &IssueError{Code: ErrCodeInvalidType}) {
+		t.Errorf("errors.Is(%v, INVALID_TYPE) = true", err)
+	}
+	var ie *IssueError
+	if !errors.As(err, &ie) {
+		t.Fatalf("expected *IssueError, got %T", err)
+	}
+	if ie.Details["value"] != "unknown" {
+		t.Errorf("Details[value] = %v, want unknown", ie.Details["value"])
+	}
+	if want := `INVALID_KIND: kind must be evidence or challenge, got "unknown" (field: Kind)`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	_, err = Issue(IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/payment",
+		SigningKey: key,
+		StrictEnv:  true,
+		Extensions: map[string]any{CommerceExtensionKey: map[string]any{"payment_rail": "stripe", "amount_minor": "100", "currency": "USD", "env": "prod"}},
+	})
+	if !errors.As(err, &ie) || ie.Details["value"] != "prod" {
+		t.Errorf("env error = %v, want Details[value] prod", err)
+	}
+}
+
 func TestIssue_RejectsMissingType(t *testing.T) {
 	key := testSigningKey(t)
 	_, err := Issue(IssueOptions{Iss: "https://example.com", Kind: KindEvidence, SigningKey: key})
@@ -296,3 +331,350 @@ func TestIssueJWS_Convenience(t *testing.T) {
 		t.Fatal("expected non-empty JWS")
 	}
 }
+
+func TestValidateIssueOptions_NoSigningKey(t *testing.T) {
+	err := ValidateIssueOptions(IssueOptions{
+		Iss:  "https://example.com",
+		Kind: KindEvidence,
+		Type: "org.peacprotocol/test",
+	})
+	if err != nil {
+		t.Fatalf("ValidateIssueOptions() without key = %v, want nil", err)
+	}
+}
+
+func TestValidateIssueOptions_MatchesIssueErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts IssueOptions
+		code string
+	}{
+		{"missing iss", IssueOptions{Kind: KindEvidence, Type: "org.peacprotocol/test"}, ErrCodeMissingIssuer},
+		{"http iss", IssueOptions{Iss: "http://example.com", Kind: KindEvidence, Type: "org.peacprotocol/test"}, ErrCodeInvalidIss},
+		{"invalid kind", IssueOptions{Iss: "https://example.com", Kind: "unknown", Type: "org.peacprotocol/test"}, ErrCodeInvalidKind},
+		{"missing type", IssueOptions{Iss: "https://example.com", Kind: KindEvidence}, ErrCodeMissingType},
+		{"invalid pillar", IssueOptions{Iss: "https://example.com", Kind: KindEvidence, Type: "org.peacprotocol/test", Pillars: []string{"nope"}}, ErrCodeInvalidPillar},
+		{"purpose too long", IssueOptions{Iss: "https://example.com", Kind: KindEvidence, Type: "org.peacprotocol/test", PurposeDeclared: strings.Repeat("x", MaxPurposeDeclaredLength+1)}, ErrCodeInvalidPurpose},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateIssueOptions(tc.opts)
+			ie, ok := err.(*IssueError)
+			if !ok {
+				t.Fatalf("expected *IssueError, got %T (%v)", err, err)
+			}
+			if ie.Code != tc.code {
+				t.Errorf("code = %s, want %s", ie.Code, tc.code)
+			}
+
+			tc.opts.SigningKey = testSigningKey(t)
+			_, issueErr := Issue(tc.opts)
+			if ie2, ok := issueErr.(*IssueError); !ok || ie2.Code != tc.code {
+				t.Errorf("Issue() err = %v, want code %s", issueErr, tc.code)
+			}
+		})
+	}
+}
+
+func TestIssue_ResultClaimsMatchSigned(t *testing.T) {
+	key := testSigningKey(t)
+	result, err := Issue(IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/test",
+		SigningKey: key,
+		Pillars:    []string{"access"},
+		Clock:      FixedClock{Time: time.Unix(1700000000, 0)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Claims == nil {
+		t.Fatal("expected Claims on IssueResult")
+	}
+
+	parts := strings.Split(result.JWS, ".")
+	payloadBytes, _ := jws.Decode(parts[1])
+	signed, _ := json.Marshal(result.Claims)
+	if string(signed) != string(payloadBytes) {
+		t.Errorf("Claims = %s, signed payload = %s", signed, payloadBytes)
+	}
+	if result.Claims.Rid != result.ReceiptID {
+		t.Errorf("Claims.Rid = %s, want %s", result.Claims.Rid, result.ReceiptID)
+	}
+	if result.Claims.PeacVersion != PeacVersion {
+		t.Errorf("Claims.PeacVersion = %s, want %s", result.Claims.PeacVersion, PeacVersion)
+	}
+}
+
+func TestIssue_OmitDefaults(t *testing.T) {
+	key := testSigningKey(t)
+	commerce := map[string]any{
+		"payment_rail": "stripe",
+		"amount_minor": "100",
+		"currency":     "USD",
+		"asset":        "USD",
+		"env":          EnvTest,
+	}
+	opts := IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/payment",
+		SigningKey: key,
+		Extensions: map[string]any{CommerceExtensionKey: commerce},
+	}
+
+	full, err := Issue(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.OmitDefaults = true
+	compact, err := Issue(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(compact.JWS) >= len(full.JWS) {
+		t.Errorf("compact JWS (%d bytes) not smaller than full (%d bytes)", len(compact.JWS), len(full.JWS))
+	}
+	payload, _ := jws.Decode(strings.Split(compact.JWS, ".")[1])
+	if strings.Contains(string(payload), `"asset"`) || !strings.Contains(string(payload), `"env":"test"`) {
+		t.Errorf("payload = %s, want asset dropped and env kept", payload)
+	}
+	if commerce["asset"] != "USD" || commerce["env"] != EnvTest {
+		t.Error("caller's extension map was modified")
+	}
+
+	result := VerifyLocal(compact.JWS, VerifyLocalOptions{PublicKey: key.PublicKey(), ExpectedEnv: EnvTest})
+	if !result.Valid {
+		t.Fatalf("VerifyLocal() failed: %s", result.ErrorMessage)
+	}
+	ext, err := result.Claims.Commerce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ext.Asset != "USD" || ext.Env != EnvTest {
+		t.Errorf("asset=%q env=%q, want USD and test", ext.Asset, ext.Env)
+	}
+}
+
+func TestIssue_DefaultsCommerceEnv(t *testing.T) {
+	key := testSigningKey(t)
+	commerce := map[string]any{"payment_rail": "stripe", "amount_minor": "100", "currency": "USD"}
+	result, err := Issue(IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/payment",
+		SigningKey: key,
+		Extensions: map[string]any{CommerceExtensionKey: commerce},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := commerce["env"]; ok {
+		t.Error("caller's extension map was modified")
+	}
+	ext, err := result.Claims.Commerce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ext.Env != EnvTest {
+		t.Errorf("issued env = %q, want %q", ext.Env, EnvTest)
+	}
+
+	// A record that omits env reads back with an empty env.
+	claims := &InteractionRecordClaims{Ext: map[string]any{CommerceExtensionKey: commerce}}
+	if ext, err := claims.Commerce(); err != nil || ext.Env != "" {
+		t.Errorf("Commerce() = %+v, %v, want empty env", ext, err)
+	}
+}
+
+func TestIssue_StrictEnv(t *testing.T) {
+	key := testSigningKey(t)
+	tests := []struct {
+		name     string
+		ext      map[string]any
+		wantCode string
+	}{
+		{"live", map[string]any{CommerceExtensionKey: map[string]any{"payment_rail": "stripe", "amount_minor": "100", "currency": "USD", "env": EnvLive}}, ""},
+		{"test", map[string]any{CommerceExtensionKey: map[string]any{"payment_rail": "stripe", "amount_minor": "100", "currency": "USD", "env": EnvTest}}, ""},
+		{"omitted", map[string]any{CommerceExtensionKey: map[string]any{"payment_rail": "stripe", "amount_minor": "100", "currency": "USD"}}, ErrCodeInvalidEnv},
+		{"unknown", map[string]any{CommerceExtensionKey: map[string]any{"payment_rail": "stripe", "amount_minor": "100", "currency": "USD", "env": "prod"}}, ErrCodeInvalidEnv},
+		{"no commerce", map[string]any{"com.example/other": map[string]any{"a": "b"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := IssueOptions{
+				Iss:        "https://example.com",
+				Kind:       KindEvidence,
+				Type:       "org.peacprotocol/payment",
+				SigningKey: key,
+				Extensions: tt.ext,
+				StrictEnv:  true,
+			}
+			_, err := Issue(opts)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("Issue() error = %v", err)
+				}
+				return
+			}
+			var ie *IssueError
+			if !errors.As(err, &ie) || ie.Code != tt.wantCode {
+				t.Fatalf("Issue() error = %v, want code %s", err, tt.wantCode)
+			}
+
+			// Without StrictEnv the omitted env defaults as before.
+			opts.StrictEnv = false
+			if tt.name == "omitted" {
+				if _, err := Issue(opts); err != nil {
+					t.Errorf("Issue() without StrictEnv error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestIssue_StandardClaims(t *testing.T) {
+	key := testSigningKey(t)
+	result, err := Issue(IssueOptions{
+		Iss:            "https://example.com",
+		Kind:           KindEvidence,
+		Type:           "org.peacprotocol/test",
+		Sub:            "https://example.com/article/1",
+		SigningKey:     key,
+		StandardClaims: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, _ := jws.Decode(strings.Split(result.JWS, ".")[1])
+	if !strings.Contains(string(payload), `"jti":"`+result.ReceiptID+`"`) {
+		t.Errorf("payload has no jti claim: %s", payload)
+	}
+
+	verified := VerifyLocal(result.JWS, VerifyLocalOptions{PublicKey: key.PublicKey(), StrictClaims: true})
+	if !verified.Valid {
+		t.Fatalf("VerifyLocal() failed: %s", verified.ErrorMessage)
+	}
+	c := verified.Claims
+	if c.Rid != result.ReceiptID || c.Jti != result.ReceiptID || c.ReceiptID() != result.ReceiptID {
+		t.Errorf("rid=%q jti=%q ReceiptID()=%q, want %q", c.Rid, c.Jti, c.ReceiptID(), result.ReceiptID)
+	}
+	if c.Sub != "https://example.com/article/1" {
+		t.Errorf("sub = %q", c.Sub)
+	}
+
+	// Without StandardClaims the record carries rid only.
+	plain, err := Issue(IssueOptions{Iss: "https://example.com", Kind: KindEvidence, Type: "org.peacprotocol/test", SigningKey: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := VerifyLocal(plain.JWS, VerifyLocalOptions{PublicKey: key.PublicKey()})
+	if !r.Valid || r.Claims.Jti != "" || r.Claims.ReceiptID() != plain.ReceiptID {
+		t.Errorf("valid=%v jti=%q ReceiptID()=%q, want %q", r.Valid, r.Claims.Jti, r.Claims.ReceiptID(), plain.ReceiptID)
+	}
+}
+
+func TestIssue_EvidenceTimeout(t *testing.T) {
+	key := testSigningKey(t)
+	items := make([]any, 5000)
+	for i := range items {
+		items[i] = "x"
+	}
+	opts := IssueOptions{
+		Iss:             "https://example.com",
+		Kind:            KindEvidence,
+		Type:            "org.peacprotocol/test",
+		SigningKey:      key,
+		Extensions:      map[string]any{"com.example/bulk": items},
+		EvidenceTimeout: time.Nanosecond,
+	}
+
+	_, err := Issue(opts)
+	issueErr, ok := err.(*IssueError)
+	if !ok || issueErr.Code != ErrCodeEvidenceTimeout {
+		t.Fatalf("Issue() error = %v, want %s", err, ErrCodeEvidenceTimeout)
+	}
+
+	opts.EvidenceTimeout = time.Minute
+	if _, err := Issue(opts); err != nil {
+		t.Errorf("Issue() with generous timeout error = %v", err)
+	}
+}
+
+func TestIssueOptions_With(t *testing.T) {
+	key := testSigningKey(t)
+	template := IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/test",
+		SigningKey: key,
+		Pillars:    []string{"access"},
+		Actor:      &ActorBinding{ID: "agent:1", ProofTypes: []string{"did"}},
+		Policy:     &PolicyBlock{Digest: "sha256:aa"},
+		Extensions: map[string]any{
+			"org.example/ctx": map[string]any{"tags": []any{"a"}, "n": 1.0},
+		},
+	}
+
+	derived := template.With(func(o *IssueOptions) {
+		o.Sub = "https://example.com/article/2"
+		o.Extensions["org.example/ctx"].(map[string]any)["n"] = 2.0
+		o.Extensions["org.example/ctx"].(map[string]any)["tags"].([]any)[0] = "b"
+		o.Pillars[0] = "commerce"
+		o.Actor.ProofTypes[0] = "x509"
+		o.Policy.Digest = "sha256:bb"
+	})
+
+	ctx := template.Extensions["org.example/ctx"].(map[string]any)
+	if ctx["n"] != 1.0 || ctx["tags"].([]any)[0] != "a" {
+		t.Errorf("template extensions changed: %v", ctx)
+	}
+	if template.Sub != "" || template.Pillars[0] != "access" ||
+		template.Actor.ProofTypes[0] != "did" || template.Policy.Digest != "sha256:aa" {
+		t.Errorf("template changed: %+v", template)
+	}
+	if derived.Sub != "https://example.com/article/2" || derived.SigningKey != key {
+		t.Errorf("derived = %+v", derived)
+	}
+
+	if _, err := Issue(derived); err != nil {
+		t.Fatalf("Issue(derived) error = %v", err)
+	}
+	if empty := (IssueOptions{}).With(); empty.Extensions != nil || empty.Actor != nil || empty.Policy != nil {
+		t.Errorf("With() on zero options = %+v", empty)
+	}
+}
+
+func TestIssue_MaxReceiptBytes(t *testing.T) {
+	key := testSigningKey(t)
+	opts := IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/test",
+		SigningKey: key,
+		Extensions: map[string]any{"org.example/note": map[string]any{"text": strings.Repeat("x", 2000)}},
+	}
+
+	unbounded, err := Issue(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := len(unbounded.JWS)
+
+	opts.MaxReceiptBytes = size + 64
+	if _, err := Issue(opts); err != nil {
+		t.Errorf("receipt under limit: %v", err)
+	}
+
+	opts.MaxReceiptBytes = 1024
+	_, err = Issue(opts)
+	var ie *IssueError
+	if !errors.As(err, &ie) || ie.Code != ErrCodeReceiptTooLarge {
+		t.Fatalf("err = %v, want %s", err, ErrCodeReceiptTooLarge)
+	}
+	if ie.Details["limit"] != 1024 || ie.Details["size"].(int) <= 1024 {
+		t.Errorf("Details = %v", ie.Details)
+	}
+}
@@ -0,0 +1,218 @@
+package peac
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/peacprotocol/peac/sdks/go/jwks"
+	"github.com/peacprotocol/peac/sdks/go/jws"
+)
+
+// IssueJSONOptions is the JSON form of IssueOptions accepted by IssueJSON.
+// SigningKey takes any encoding jws.LoadSigningKey accepts (PEM, hex, or
+// base64 seed or private key). The evidence timeout is in milliseconds.
+type IssueJSONOptions struct {
+	Iss               string         `json:"iss"`
+	Kind              string         `json:"kind"`
+	Type              string         `json:"type"`
+	SigningKey        string         `json:"signing_key"`
+	Kid               string         `json:"kid"`
+	Sub               string         `json:"sub,omitempty"`
+	Exp               int64          `json:"exp,omitempty"`
+	Pillars           []string       `json:"pillars,omitempty"`
+	PurposeDeclared   string         `json:"purpose_declared,omitempty"`
+	Actor             *ActorBinding  `json:"actor,omitempty"`
+	Extensions        map[string]any `json:"extensions,omitempty"`
+	Policy            *PolicyBlock   `json:"policy,omitempty"`
+	EvidenceTimeoutMs int64          `json:"evidence_timeout_ms,omitempty"`
+	MaxReceiptBytes   int            `json:"max_receipt_bytes,omitempty"`
+	OmitDefaults      bool           `json:"omit_defaults,omitempty"`
+	StrictEnv         bool           `json:"strict_env,omitempty"`
+	StandardClaims    bool           `json:"standard_claims,omitempty"`
+}
+
+// VerifyJSONOptions is the JSON form of VerifyLocalOptions accepted by
+// VerifyJSON. PublicKey is a single Ed25519 JWK; JWKS is a key set resolved
+// by the record's kid when PublicKey is absent. Policy is the local policy
+// document checked against the record's policy binding. Durations are in
+// seconds, except the evidence timeout in milliseconds; MinIssuedAt is Unix
+// seconds.
+type VerifyJSONOptions struct {
+	PublicKey               json.RawMessage `json:"public_key,omitempty"`
+	JWKS                    *jwks.JWKS      `json:"jwks,omitempty"`
+	Issuer                  string          `json:"issuer,omitempty"`
+	TrustedIssuers          []string        `json:"trusted_issuers,omitempty"`
+	MaxClockSkewSeconds     int64           `json:"max_clock_skew_seconds,omitempty"`
+	MaxFutureSkewSeconds    int64           `json:"max_future_skew_seconds,omitempty"`
+	MaxAgeSeconds           int64           `json:"max_age_seconds,omitempty"`
+	MaxAgeLeewayFraction    float64         `json:"max_age_leeway_fraction,omitempty"`
+	MinIssuedAt             int64           `json:"min_issued_at,omitempty"`
+	RequireExp              bool            `json:"require_exp,omitempty"`
+	AllowedKeyIDs           []string        `json:"allowed_key_ids,omitempty"`
+	AllowedAlgorithms       []string        `json:"allowed_algorithms,omitempty"`
+	ExpectedEnv             string          `json:"expected_env,omitempty"`
+	ExpectedCurrency        string          `json:"expected_currency,omitempty"`
+	MinAmount               int64           `json:"min_amount,omitempty"`
+	CheckPaymentConsistency bool            `json:"check_payment_consistency,omitempty"`
+	StrictClaims            bool            `json:"strict_claims,omitempty"`
+	ValidateEvidence        bool            `json:"validate_evidence,omitempty"`
+	EvidenceTimeoutMs       int64           `json:"evidence_timeout_ms,omitempty"`
+	Policy                  string          `json:"policy,omitempty"`
+}
+
+// JSONError is the structured error in IssueJSON and VerifyJSON output.
+type JSONError struct {
+	Code    string         `json:"code"`
+	Message string         `json:"message"`
+	Field   string         `json:"field,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// IssueJSONResult is the output of IssueJSON. On failure, OK is false and
+// Error carries the IssueError code, field, and details.
+type IssueJSONResult struct {
+	OK        bool                     `json:"ok"`
+	JWS       string                   `json:"jws,omitempty"`
+	ReceiptID string                   `json:"receipt_id,omitempty"`
+	IssuedAt  int64                    `json:"issued_at,omitempty"`
+	Claims    *InteractionRecordClaims `json:"claims,omitempty"`
+	Error     *JSONError               `json:"error,omitempty"`
+}
+
+// VerifyJSONResult is the output of VerifyJSON. On failure, Valid is false
+// and Error carries the VerifyLocal error code and message.
+type VerifyJSONResult struct {
+	Valid         bool                     `json:"valid"`
+	Claims        *InteractionRecordClaims `json:"claims,omitempty"`
+	Kid           string                   `json:"kid,omitempty"`
+	KeyStatus     string                   `json:"key_status,omitempty"`
+	Algorithm     string                   `json:"algorithm,omitempty"`
+	WireVersion   string                   `json:"wire_version,omitempty"`
+	ReceiptRef    string                   `json:"receipt_ref,omitempty"`
+	PolicyBinding PolicyBindingStatus      `json:"policy_binding,omitempty"`
+	Warnings      []VerificationWarning    `json:"warnings,omitempty"`
+	Perf          *VerifyPerf              `json:"perf"`
+	Error         *JSONError               `json:"error,omitempty"`
+}
+
+// IssueJSON issues a record from JSON-encoded IssueJSONOptions and returns a
+// JSON-encoded IssueJSONResult, so a CLI or language binding needs no Go
+// types. Issuance failures are reported in the output; an error is returned
+// only when optsJSON is malformed or its signing key cannot be loaded.
+func IssueJSON(optsJSON []byte) ([]byte, error) {
+	var in IssueJSONOptions
+	if err := json.Unmarshal(optsJSON, &in); err != nil {
+		return nil, fmt.Errorf("invalid issue options: %w", err)
+	}
+	var key *jws.SigningKey
+	if in.SigningKey != "" {
+		var err error
+		if key, err = jws.LoadSigningKey(in.SigningKey, in.Kid); err != nil {
+			return nil, fmt.Errorf("invalid signing_key: %w", err)
+		}
+	}
+
+	issued, err := Issue(IssueOptions{
+		Iss:             in.Iss,
+		Kind:            in.Kind,
+		Type:            in.Type,
+		SigningKey:      key,
+		Kid:             in.Kid,
+		Sub:             in.Sub,
+		Exp:             in.Exp,
+		Pillars:         in.Pillars,
+		PurposeDeclared: in.PurposeDeclared,
+		Actor:           in.Actor,
+		Extensions:      in.Extensions,
+		Policy:          in.Policy,
+		EvidenceTimeout: time.Duration(in.EvidenceTimeoutMs) * time.Millisecond,
+		MaxReceiptBytes: in.MaxReceiptBytes,
+		OmitDefaults:    in.OmitDefaults,
+		StrictEnv:       in.StrictEnv,
+		StandardClaims:  in.StandardClaims,
+	})
+	if err != nil {
+		jsonErr := &JSONError{Code: ErrCodeSignFailed, Message: err.Error()}
+		var ie *IssueError
+		if errors.As(err, &ie) {
+			jsonErr = &JSONError{Code: ie.Code, Message: ie.Message, Field: ie.Field, Details: ie.Details}
+		}
+		return json.Marshal(IssueJSONResult{Error: jsonErr})
+	}
+	return json.Marshal(IssueJSONResult{
+		OK:        true,
+		JWS:       issued.JWS,
+		ReceiptID: issued.ReceiptID,
+		IssuedAt:  issued.IssuedAt,
+		Claims:    issued.Claims,
+	})
+}
+
+// VerifyJSON verifies a record with JSON-encoded VerifyJSONOptions and
+// returns a JSON-encoded VerifyJSONResult, including timing. Verification
+// failures are reported in the output; an error is returned only when
+// optsJSON is malformed or its keys cannot be parsed.
+func VerifyJSON(receiptJWS string, optsJSON []byte) ([]byte, error) {
+	var in VerifyJSONOptions
+	if err := json.Unmarshal(optsJSON, &in); err != nil {
+		return nil, fmt.Errorf("invalid verify options: %w", err)
+	}
+	opts := VerifyLocalOptions{
+		Issuer:                  in.Issuer,
+		TrustedIssuers:          in.TrustedIssuers,
+		MaxClockSkew:            time.Duration(in.MaxClockSkewSeconds) * time.Second,
+		MaxFutureSkew:           time.Duration(in.MaxFutureSkewSeconds) * time.Second,
+		MaxAge:                  time.Duration(in.MaxAgeSeconds) * time.Second,
+		MaxAgeLeewayFraction:    in.MaxAgeLeewayFraction,
+		RequireExp:              in.RequireExp,
+		AllowedKeyIDs:           in.AllowedKeyIDs,
+		AllowedAlgorithms:       in.AllowedAlgorithms,
+		ExpectedEnv:             in.ExpectedEnv,
+		ExpectedCurrency:        in.ExpectedCurrency,
+		MinAmount:               in.MinAmount,
+		CheckPaymentConsistency: in.CheckPaymentConsistency,
+		StrictClaims:            in.StrictClaims,
+		ValidateEvidence:        in.ValidateEvidence,
+		EvidenceTimeout:         time.Duration(in.EvidenceTimeoutMs) * time.Millisecond,
+	}
+	if in.MinIssuedAt > 0 {
+		opts.MinIssuedAt = time.Unix(in.MinIssuedAt, 0)
+	}
+	if in.Policy != "" {
+		opts.PolicyBytes = []byte(in.Policy)
+	}
+	if len(in.PublicKey) > 0 {
+		key, err := jws.ParsePublicKeyJWK(in.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public_key: %w", err)
+		}
+		opts.PublicKey = key
+	} else if in.JWKS != nil {
+		keySet, err := in.JWKS.ToKeySet()
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwks: %w", err)
+		}
+		opts.KeySet = keySet
+	}
+
+	start := time.Now()
+	result := VerifyLocal(receiptJWS, opts)
+	out := VerifyJSONResult{
+		Valid:         result.Valid,
+		Claims:        result.Claims,
+		Kid:           result.Kid,
+		KeyStatus:     result.KeyStatus,
+		Algorithm:     result.Algorithm,
+		WireVersion:   result.WireVersion,
+		ReceiptRef:    result.ReceiptRef,
+		PolicyBinding: result.PolicyBinding,
+		Warnings:      result.Warnings,
+		Perf:          &VerifyPerf{VerifyMs: float64(time.Since(start).Microseconds()) / 1000},
+	}
+	if !result.Valid {
+		out.Error = &JSONError{Code: result.ErrorCode, Message: result.ErrorMessage}
+	}
+	return json.Marshal(out)
+}
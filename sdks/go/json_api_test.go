@@ -0,0 +1,158 @@
+package peac
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peacprotocol/peac/sdks/go/jwks"
+	"github.com/peacprotocol/peac/sdks/go/jws"
+)
+
+func TestIssueJSON_VerifyJSON(t *testing.T) {
+	seed := strings.Repeat("01", 32)
+	raw, _ := hex.DecodeString(seed)
+	key, err := jws.NewSigningKeyFromSeed(raw, "k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := IssueJSON([]byte(`{"iss":"https://example.com","kind":"evidence","type":"org.peacprotocol/test","signing_key":"` + seed + `","kid":"k1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var issued IssueJSONResult
+	if err := json.Unmarshal(out, &issued); err != nil {
+		t.Fatal(err)
+	}
+	if !issued.OK || issued.JWS == "" || issued.Claims.Rid != issued.ReceiptID {
+		t.Fatalf("IssueJSON() = %s", out)
+	}
+
+	jwk, _ := json.Marshal(key.JWK("", time.Time{}))
+	for name, opts := range map[string]string{
+		"public_key": `{"public_key":` + string(jwk) + `,"issuer":"https://example.com"}`,
+		"jwks":       `{"jwks":{"keys":[` + string(jwk) + `]}}`,
+	} {
+		out, err := VerifyJSON(issued.JWS, []byte(opts))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var verified VerifyJSONResult
+		if err := json.Unmarshal(out, &verified); err != nil {
+			t.Fatal(err)
+		}
+		if !verified.Valid || verified.Claims.Rid != issued.ReceiptID || verified.Kid != "k1" || verified.Perf == nil {
+			t.Errorf("%s: VerifyJSON() = %s", name, out)
+		}
+	}
+}
+
+func TestIssueJSON_Failure(t *testing.T) {
+	out, err := IssueJSON([]byte(`{"iss":"https://example.com","kind":"bogus","type":"org.peacprotocol/test","signing_key":"` + strings.Repeat("01", 32) + `","kid":"k1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result IssueJSONResult
+	if err := json.Unmarshal(out, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.OK || result.Error == nil || result.Error.Code != ErrCodeInvalidKind || result.Error.Field != "Kind" || result.Error.Details["value"] != "bogus" {
+		t.Errorf("IssueJSON() = %s, want INVALID_KIND with details", out)
+	}
+
+	opts, _ := json.Marshal(IssueJSONOptions{Iss: "https://example.com", Kind: KindEvidence, Type: "org.peacprotocol/test"})
+	out, err = IssueJSON(opts)
+	if err != nil || !strings.Contains(string(out), `"code":"`+ErrCodeMissingKey+`"`) {
+		t.Errorf("IssueJSON() without key = %s, %v, want MISSING_SIGNING_KEY", out, err)
+	}
+
+	if _, err := IssueJSON([]byte(`{"iss":`)); err == nil {
+		t.Error("malformed options should return an error")
+	}
+	if _, err := IssueJSON([]byte(`{"signing_key":"not a key"}`)); err == nil {
+		t.Error("unloadable signing key should return an error")
+	}
+}
+
+func TestVerifyJSON_Failure(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("k1")
+	other, _ := jws.GenerateSigningKey("k1")
+	signed, err := IssueJWS(IssueOptions{Iss: "https://example.com", Kind: KindEvidence, Type: "org.peacprotocol/test", SigningKey: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := jwks.JWKS{Keys: []jwks.JWK{other.JWK("", time.Time{})}}
+	opts, _ := json.Marshal(VerifyJSONOptions{JWKS: &set})
+	out, err := VerifyJSON(signed, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result VerifyJSONResult
+	if err := json.Unmarshal(out, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid || result.Error == nil || result.Error.Code != "E_INVALID_SIGNATURE" || result.ReceiptRef == "" {
+		t.Errorf("VerifyJSON() = %s, want E_INVALID_SIGNATURE", out)
+	}
+
+	if _, err := VerifyJSON(signed, []byte(`{"public_key":{"kty":"RSA"}}`)); err == nil {
+		t.Error("unparseable public_key should return an error")
+	}
+}
+
+func TestIssueJSON_VerifyJSON_Binding(t *testing.T) {
+	seed := strings.Repeat("01", 32)
+	raw, _ := hex.DecodeString(seed)
+	key, _ := jws.NewSigningKeyFromSeed(raw, "k1")
+	keyJWK, _ := json.Marshal(key.JWK("", time.Time{}))
+
+	out, err := IssueJSON([]byte(`{"iss":"https://example.com","kind":"evidence","type":"org.peacprotocol/test","signing_key":"` + seed + `","kid":"k1","evidence_timeout_ms":1000}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var issued IssueJSONResult
+	if err := json.Unmarshal(out, &issued); err != nil {
+		t.Fatal(err)
+	}
+	if !issued.OK {
+		t.Fatalf("IssueJSON() = %s", out)
+	}
+
+	tests := []struct {
+		name     string
+		opts     string
+		wantCode string
+		binding  PolicyBindingStatus
+	}{
+		{"no extra checks", `"issuer":"https://example.com"`, "", ""},
+		{"issued before cutoff", `"min_issued_at":` + strconv.FormatInt(issued.IssuedAt+60, 10), "E_ISSUED_BEFORE_CUTOFF", ""},
+		{"policy without binding", `"policy":"version: 1","evidence_timeout_ms":1000`, "", PolicyBindingUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := VerifyJSON(issued.JWS, []byte(`{"public_key":`+string(keyJWK)+`,`+tt.opts+`}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var verified VerifyJSONResult
+			if err := json.Unmarshal(out, &verified); err != nil {
+				t.Fatal(err)
+			}
+			if code := jsonCode(verified.Error); code != tt.wantCode || (tt.binding != "" && verified.PolicyBinding != tt.binding) {
+				t.Errorf("VerifyJSON() = %s, want code %q binding %q", out, tt.wantCode, tt.binding)
+			}
+		})
+	}
+
+}
+
+func jsonCode(e *JSONError) string {
+	if e == nil {
+		return ""
+	}
+	return e.Code
+}
@@ -2,21 +2,28 @@ package jwks
 
 import (
 	"context"
+	"errors"
+	"math/rand/v2"
 	"sync"
 	"time"
 )
 
 // Cache is a thread-safe JWKS cache.
 type Cache struct {
-	mu      sync.RWMutex
-	entries map[string]*cacheEntry
-	opts    CacheOptions
+	mu         sync.RWMutex
+	entries    map[string]*cacheEntry
+	refreshing map[string]bool
+	opts       CacheOptions
 }
 
 type cacheEntry struct {
 	keySet    *KeySet
 	expiresAt time.Time
 	fetchedAt time.Time
+
+	// droppedAt records when each retained kid first went missing from the
+	// fetched JWKS (see CacheOptions.RotationGrace).
+	droppedAt map[string]time.Time
 }
 
 // CacheOptions configures the JWKS cache.
@@ -24,9 +31,33 @@ type CacheOptions struct {
 	// TTL is the time-to-live for cached entries.
 	TTL time.Duration
 
-	// StaleWhileRevalidate allows using stale entries while fetching fresh ones.
+	// TTLJitter randomizes each entry's lifetime within [TTL-TTLJitter, TTL]
+	// so entries cached in the same burst do not all expire, and re-fetch,
+	// at once. Zero disables jitter; values above TTL are capped at TTL.
+	TTLJitter time.Duration
+
+	// StaleWhileRevalidate serves a stale entry immediately and refreshes it
+	// in the background. A failed background refresh keeps the stale entry
+	// and is retried on the next Get.
 	StaleWhileRevalidate bool
 
+	// RotationGrace keeps a key that disappears from the JWKS in the cache
+	// for this long after the refresh that first misses it, marked
+	// StatusDeprecated, so records signed just before a rotation still
+	// verify until they expire. Zero replaces the key set outright on each
+	// refresh. Keys removed because of compromise should be revoked through
+	// VerifyLocalOptions.AllowedKeyIDs or Invalidate instead.
+	RotationGrace time.Duration
+
+	// OnRefresh, if set, is called after every fetch attempt with the URL
+	// and the fetch error (nil on success). servedStale reports that the
+	// refresh ran in the background while a stale entry was served; a
+	// failure with servedStale set means callers keep getting expired keys,
+	// which usually precedes an outage and is worth alerting on. It is
+	// called without the cache lock held, from the refreshing goroutine, so
+	// it must be safe for concurrent use.
+	OnRefresh func(url string, err error, servedStale bool)
+
 	// FetchOptions configures how JWKS are fetched.
 	FetchOptions FetchOptions
 }
@@ -46,12 +77,17 @@ func NewCache(opts CacheOptions) *Cache {
 		opts.TTL = 5 * time.Minute
 	}
 	return &Cache{
-		entries: make(map[string]*cacheEntry),
-		opts:    opts,
+		entries:    make(map[string]*cacheEntry),
+		refreshing: make(map[string]bool),
+		opts:       opts,
 	}
 }
 
 // Get retrieves a KeySet for the given URL, fetching if necessary.
+//
+// If ctx is canceled or its deadline passes while fetching and there is no
+// stale entry to fall back on, the returned error is ctx.Err() rather than a
+// fetch error, so callers can tell cancellation from an unreachable JWKS.
 func (c *Cache) Get(ctx context.Context, url string) (*KeySet, error) {
 	c.mu.RLock()
 	entry, exists := c.entries[url]
@@ -61,37 +97,134 @@ func (c *Cache) Get(ctx context.Context, url string) (*KeySet, error) {
 		return entry.keySet, nil
 	}
 
+	if exists && c.opts.StaleWhileRevalidate {
+		c.refreshInBackground(ctx, url)
+		return entry.keySet, nil
+	}
+
 	// Need to fetch fresh data
-	return c.refresh(ctx, url, entry)
+	keySet, err := c.refresh(ctx, url)
+	c.notifyRefresh(url, err, false)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+	return keySet, nil
 }
 
-func (c *Cache) refresh(ctx context.Context, url string, staleEntry *cacheEntry) (*KeySet, error) {
+// refreshInBackground starts a refresh for url unless one is already in
+// flight. The refresh runs on a context detached from ctx so it is not
+// canceled when the request that triggered it completes; the fetch timeout
+// in FetchOptions still bounds it.
+func (c *Cache) refreshInBackground(ctx context.Context, url string) {
+	c.mu.Lock()
+	if c.refreshing[url] {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing[url] = true
+	c.mu.Unlock()
+
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.refreshing, url)
+			c.mu.Unlock()
+		}()
+		_, err := c.refresh(context.WithoutCancel(ctx), url)
+		c.notifyRefresh(url, err, true)
+	}()
+}
+
+// notifyRefresh reports a refresh outcome to OnRefresh. Callers must not
+// hold c.mu.
+func (c *Cache) notifyRefresh(url string, err error, servedStale bool) {
+	if c.opts.OnRefresh != nil {
+		c.opts.OnRefresh(url, err, servedStale)
+	}
+}
+
+func (c *Cache) refresh(ctx context.Context, url string) (*KeySet, error) {
 	jwks, err := Fetch(ctx, url, c.opts.FetchOptions)
 	if err != nil {
-		// If we have stale data and StaleWhileRevalidate is enabled, return stale
-		if staleEntry != nil && c.opts.StaleWhileRevalidate {
-			return staleEntry.keySet, nil
-		}
 		return nil, err
 	}
 
-	keySet, err := jwks.ToKeySet()
-	if err != nil {
-		if staleEntry != nil && c.opts.StaleWhileRevalidate {
-			return staleEntry.keySet, nil
-		}
+	// A JWKS with some supported keys is usable; one with none is a
+	// misconfiguration worth reporting distinctly from a missing kid.
+	keySet, err := jwks.ToKeySetStrict()
+	if err != nil && errors.Is(err, ErrNoSupportedKeys) {
 		return nil, err
 	}
 
-	c.mu.Lock()
-	c.entries[url] = &cacheEntry{
+	now := time.Now()
+	entry := &cacheEntry{
 		keySet:    keySet,
-		expiresAt: time.Now().Add(c.opts.TTL),
-		fetchedAt: time.Now(),
+		expiresAt: now.Add(c.ttl()),
+		fetchedAt: now,
+	}
+	c.mu.Lock()
+	if prev := c.entries[url]; prev != nil && c.opts.RotationGrace > 0 {
+		c.retainDropped(prev, entry, revokedKeyIDs(jwks), now)
 	}
+	c.entries[url] = entry
 	c.mu.Unlock()
 
-	return keySet, nil
+	return entry.keySet, nil
+}
+
+// retainDropped merges into entry the kids of prev that the fresh fetch no
+// longer lists, for as long as they are within RotationGrace of first
+// going missing. Kids the fetch marks revoked are never retained: a
+// revocation is not a rotation.
+func (c *Cache) retainDropped(prev, entry *cacheEntry, revoked map[string]bool, now time.Time) {
+	var grace []string
+	for _, kid := range prev.keySet.KeyIDs() {
+		if _, listed := entry.keySet.keys[kid]; listed || revoked[kid] {
+			continue
+		}
+		dropped, ok := prev.droppedAt[kid]
+		if !ok {
+			dropped = now
+		}
+		if now.Sub(dropped) >= c.opts.RotationGrace {
+			continue
+		}
+		if entry.droppedAt == nil {
+			entry.droppedAt = make(map[string]time.Time)
+		}
+		entry.droppedAt[kid] = dropped
+		grace = append(grace, kid)
+	}
+	if len(grace) > 0 {
+		entry.keySet = prev.keySet.MergeRetaining(entry.keySet, grace)
+	}
+}
+
+// revokedKeyIDs returns the kids j lists with StatusRevoked, which
+// ToKeySet leaves out of the key set.
+func revokedKeyIDs(j *JWKS) map[string]bool {
+	var revoked map[string]bool
+	for _, jwk := range j.Keys {
+		if jwk.Status == StatusRevoked {
+			if revoked == nil {
+				revoked = make(map[string]bool)
+			}
+			revoked[jwk.KeyID] = true
+		}
+	}
+	return revoked
+}
+
+// ttl returns the lifetime for a new entry, with jitter applied.
+func (c *Cache) ttl() time.Duration {
+	jitter := min(c.opts.TTLJitter, c.opts.TTL)
+	if jitter <= 0 {
+		return c.opts.TTL
+	}
+	return c.opts.TTL - rand.N(jitter+1)
 }
 
 // Set manually sets a KeySet in the cache.
@@ -101,7 +234,7 @@ func (c *Cache) Set(url string, keySet *KeySet) {
 
 	c.entries[url] = &cacheEntry{
 		keySet:    keySet,
-		expiresAt: time.Now().Add(c.opts.TTL),
+		expiresAt: time.Now().Add(c.ttl()),
 		fetchedAt: time.Now(),
 	}
 }
@@ -0,0 +1,300 @@
+package jwks
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func jwksServer(t *testing.T, delay time.Duration, pub ed25519.PublicKey) *httptest.Server {
+	t.Helper()
+	body, _ := json.Marshal(JWKS{Keys: []JWK{{
+		KeyType: "OKP",
+		Curve:   "Ed25519",
+		KeyID:   "fresh",
+		X:       base64.RawURLEncoding.EncodeToString(pub),
+	}}})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCache_CancellationWithoutStaleEntry(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(nil)
+	srv := jwksServer(t, time.Second, pub)
+	cache := NewCache(DefaultCacheOptions())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	_, err := cache.Get(ctx, srv.URL)
+	if err != context.Canceled {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestCache_StaleRefreshSurvivesCallerCancellation(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(nil)
+	srv := jwksServer(t, 50*time.Millisecond, pub)
+
+	opts := DefaultCacheOptions()
+	opts.TTL = time.Nanosecond
+	cache := NewCache(opts)
+
+	stale := NewKeySet()
+	stale.Add("stale", pub)
+	cache.Set(srv.URL, stale)
+	time.Sleep(time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	got, err := cache.Get(ctx, srv.URL)
+	cancel() // the triggering request completes immediately
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != stale {
+		t.Fatal("expected stale key set to be served")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		cache.mu.RLock()
+		current := cache.entries[srv.URL].keySet
+		cache.mu.RUnlock()
+		if _, ok := current.Get("fresh"); ok {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("background refresh did not replace the stale entry")
+}
+
+func TestCache_FetchErrorIsNotCancellation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	_, err := NewCache(DefaultCacheOptions()).Get(context.Background(), srv.URL)
+	if err == nil || errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want fetch error", err)
+	}
+}
+
+func TestCache_TTLJitter(t *testing.T) {
+	opts := DefaultCacheOptions()
+	opts.TTL = time.Hour
+	opts.TTLJitter = 10 * time.Minute
+	cache := NewCache(opts)
+
+	before := time.Now()
+	cache.Set("https://a.example/jwks.json", NewKeySet())
+	cache.Set("https://b.example/jwks.json", NewKeySet())
+	after := time.Now()
+
+	a := cache.entries["https://a.example/jwks.json"].expiresAt
+	b := cache.entries["https://b.example/jwks.json"].expiresAt
+	if a.Equal(b) {
+		t.Error("entries inserted together share an expiry despite jitter")
+	}
+	for _, exp := range []time.Time{a, b} {
+		if exp.Before(before.Add(opts.TTL-opts.TTLJitter)) || exp.After(after.Add(opts.TTL)) {
+			t.Errorf("expiry %s outside [TTL-jitter, TTL]", exp.Sub(before))
+		}
+	}
+}
+
+func TestCache_TTLJitterCappedAtTTL(t *testing.T) {
+	opts := DefaultCacheOptions()
+	opts.TTL = time.Minute
+	opts.TTLJitter = time.Hour
+	cache := NewCache(opts)
+	for i := 0; i < 100; i++ {
+		if ttl := cache.ttl(); ttl < 0 || ttl > opts.TTL {
+			t.Fatalf("ttl() = %s, want within [0, %s]", ttl, opts.TTL)
+		}
+	}
+}
+
+func TestCache_OnRefresh(t *testing.T) {
+	type event struct {
+		url         string
+		failed      bool
+		servedStale bool
+	}
+	var (
+		mu     sync.Mutex
+		events []event
+		cache  *Cache
+	)
+	opts := DefaultCacheOptions()
+	opts.TTL = time.Nanosecond
+	opts.OnRefresh = func(url string, err error, servedStale bool) {
+		// Deadlocks if the callback runs under the cache lock.
+		cache.Invalidate("https://unrelated.example")
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event{url, err != nil, servedStale})
+	}
+	cache = NewCache(opts)
+
+	pub, _, _ := ed25519.GenerateKey(nil)
+	ok := jwksServer(t, 0, pub)
+	if _, err := cache.Get(context.Background(), ok.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	cache.Set(failing.URL, NewKeySet())
+	time.Sleep(time.Millisecond)
+	if _, err := cache.Get(context.Background(), failing.URL); err != nil {
+		t.Fatalf("Get() with stale entry error = %v", err)
+	}
+
+	want := []event{{ok.URL, false, false}, {failing.URL, true, true}}
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(events)
+		mu.Unlock()
+		if n >= len(want) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(events, want) {
+		t.Errorf("OnRefresh events = %+v, want %+v", events, want)
+	}
+}
+
+// rotationHarness serves a replaceable key set through a cache that
+// refetches on every get and retains dropped keys for a short grace period.
+type rotationHarness struct {
+	t      *testing.T
+	mu     sync.Mutex
+	served JWKS
+	keys   map[string]ed25519.PublicKey
+	url    string
+	cache  *Cache
+	grace  time.Duration
+}
+
+func newRotationHarness(t *testing.T) *rotationHarness {
+	oldKey, _, _ := ed25519.GenerateKey(nil)
+	newKey, _, _ := ed25519.GenerateKey(nil)
+	h := &rotationHarness{t: t, keys: map[string]ed25519.PublicKey{"old": oldKey, "new": newKey}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(h.served)
+	}))
+	t.Cleanup(srv.Close)
+	h.url = srv.URL
+
+	opts := DefaultCacheOptions()
+	opts.TTL = time.Nanosecond
+	opts.StaleWhileRevalidate = false
+	opts.RotationGrace = 100 * time.Millisecond
+	h.cache = NewCache(opts)
+	h.grace = opts.RotationGrace
+	return h
+}
+
+// jwk returns the JWK for kid, "old" or "new".
+func (h *rotationHarness) jwk(kid string) JWK {
+	return JWK{KeyType: "OKP", Curve: "Ed25519", KeyID: kid, X: base64.RawURLEncoding.EncodeToString(h.keys[kid])}
+}
+
+// serve replaces the served key set.
+func (h *rotationHarness) serve(keys ...JWK) {
+	h.mu.Lock()
+	h.served = JWKS{Keys: keys}
+	h.mu.Unlock()
+}
+
+// get refreshes and returns the cached key set.
+func (h *rotationHarness) get() *KeySet {
+	h.t.Helper()
+	time.Sleep(time.Millisecond)
+	ks, err := h.cache.Get(context.Background(), h.url)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	return ks
+}
+
+func TestCache_RotationGrace(t *testing.T) {
+	h := newRotationHarness(t)
+	h.serve(h.jwk("old"))
+	h.get()
+	h.serve(h.jwk("new"))
+
+	ks := h.get()
+	if _, ok := ks.Get("old"); !ok {
+		t.Fatal("dropped key not retained within grace period")
+	}
+	if status, _ := ks.Status("old"); status != StatusDeprecated {
+		t.Errorf("Status(old) = %q, want %q", status, StatusDeprecated)
+	}
+	if _, ok := ks.Get("new"); !ok {
+		t.Error("new key missing")
+	}
+	if _, ok := h.get().Get("old"); !ok {
+		t.Error("dropped key not retained across a second refresh within grace")
+	}
+
+	time.Sleep(h.grace)
+	if _, ok := h.get().Get("old"); ok {
+		t.Error("dropped key retained past grace period")
+	}
+}
+
+func TestCache_RotationGraceRevoked(t *testing.T) {
+	h := newRotationHarness(t)
+	revoked := h.jwk("old")
+	revoked.Status = StatusRevoked
+
+	h.serve(h.jwk("old"))
+	h.get()
+	h.serve(h.jwk("new"))
+	if _, ok := h.get().Get("old"); !ok {
+		t.Fatal("dropped key not retained within grace period")
+	}
+
+	// Revoking the key mid-grace removes it at the next refresh.
+	h.serve(h.jwk("new"), revoked)
+	if _, ok := h.get().Get("old"); ok {
+		t.Error("revoked key retained during grace period")
+	}
+
+	// A revocation on the very refresh that drops the key is not retained either.
+	h.cache.Clear()
+	h.serve(h.jwk("old"))
+	h.get()
+	h.serve(h.jwk("new"), revoked)
+	if _, ok := h.get().Get("old"); ok {
+		t.Error("key revoked on drop was retained")
+	}
+}
@@ -4,11 +4,19 @@ package jwks
 import (
 	"context"
 	"crypto/ed25519"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"mime"
 	"net/http"
+	"net/url"
+	"path"
+	"runtime/debug"
+	"slices"
 	"strings"
 	"time"
 )
@@ -38,29 +46,153 @@ type JWK struct {
 	ValidFrom string `json:"peac:valid_from,omitempty"`
 }
 
-// KeySet holds a set of public keys indexed by key ID.
+// Key status values (JWK.Status, peac:status).
+const (
+	// StatusActive keys may be used for issuance and verification. A key
+	// without peac:status is active.
+	StatusActive = "active"
+
+	// StatusDeprecated keys still verify existing records, but issuers
+	// should stop signing with them.
+	StatusDeprecated = "deprecated"
+
+	// StatusRevoked keys are excluded from key sets entirely.
+	StatusRevoked = "revoked"
+)
+
+// KeySet holds a set of public keys indexed by key ID. A kid may map to
+// several candidate keys, as during an overlapping rotation or when a JWKS
+// lists duplicates; verifiers should accept a signature from any of them.
+// Each candidate carries its own status.
 type KeySet struct {
-	keys      map[string]ed25519.PublicKey
+	keys      map[string][]keyEntry
 	fetchedAt time.Time
 	expiresAt time.Time
 }
 
+// keyEntry is one candidate key under a kid.
+type keyEntry struct {
+	key    ed25519.PublicKey
+	status string
+}
+
 // NewKeySet creates a new empty KeySet.
 func NewKeySet() *KeySet {
 	return &KeySet{
-		keys: make(map[string]ed25519.PublicKey),
+		keys: make(map[string][]keyEntry),
 	}
 }
 
-// Add adds a key to the set.
+// Add adds an active key to the set.
 func (ks *KeySet) Add(kid string, key ed25519.PublicKey) {
-	ks.keys[kid] = key
+	ks.AddWithStatus(kid, key, StatusActive)
 }
 
-// Get retrieves a key by ID.
+// AddWithStatus adds a key with the given status. An empty status is
+// StatusActive. A key already present under kid becomes an additional
+// candidate rather than replacing it; re-adding an identical key only
+// updates its status, and an active key stays active.
+func (ks *KeySet) AddWithStatus(kid string, key ed25519.PublicKey, status string) {
+	if status == "" {
+		status = StatusActive
+	}
+	entries := ks.keys[kid]
+	i := slices.IndexFunc(entries, func(e keyEntry) bool { return e.key.Equal(key) })
+	if i < 0 {
+		ks.keys[kid] = append(entries, keyEntry{key: key, status: status})
+		return
+	}
+	if entries[i].status != StatusActive {
+		entries[i].status = status
+	}
+}
+
+// Status returns the status of the kid: StatusActive if any candidate under
+// it is active, otherwise the status of the first. Use KeyStatus for the
+// status of the particular key a record verified under.
+func (ks *KeySet) Status(kid string) (string, bool) {
+	entries := ks.keys[kid]
+	if len(entries) == 0 {
+		return "", false
+	}
+	if slices.ContainsFunc(entries, func(e keyEntry) bool { return e.status == StatusActive }) {
+		return StatusActive, true
+	}
+	return entries[0].status, true
+}
+
+// KeyStatus returns the status of key under kid, so callers can warn when a
+// record verified under a StatusDeprecated key even while another candidate
+// sharing the kid is active. The second return is false if key is not a
+// candidate under kid.
+func (ks *KeySet) KeyStatus(kid string, key ed25519.PublicKey) (string, bool) {
+	for _, e := range ks.keys[kid] {
+		if e.key.Equal(key) {
+			return e.status, true
+		}
+	}
+	return "", false
+}
+
+// Get retrieves a key by ID: the first candidate added under kid. Use
+// Candidates to verify against every key sharing the kid.
 func (ks *KeySet) Get(kid string) (ed25519.PublicKey, bool) {
-	key, ok := ks.keys[kid]
-	return key, ok
+	entries := ks.keys[kid]
+	if len(entries) == 0 {
+		return nil, false
+	}
+	return entries[0].key, true
+}
+
+// Candidates returns every key added under kid, in insertion order, or nil
+// if there is none.
+func (ks *KeySet) Candidates(kid string) []ed25519.PublicKey {
+	entries := ks.keys[kid]
+	if len(entries) == 0 {
+		return nil
+	}
+	keys := make([]ed25519.PublicKey, len(entries))
+	for i, e := range entries {
+		keys[i] = e.key
+	}
+	return keys
+}
+
+// KeyIDs returns the IDs of the keys in the set, sorted.
+func (ks *KeySet) KeyIDs() []string {
+	return slices.Sorted(maps.Keys(ks.keys))
+}
+
+// Len returns the number of key IDs in the set.
+func (ks *KeySet) Len() int {
+	return len(ks.keys)
+}
+
+// MergeRetaining returns a new KeySet holding every key in newer plus, for
+// each kid in graceKeys, the keys ks has under it, so records signed under
+// a key just dropped from the JWKS still verify during a rotation grace
+// period. A retained key that newer no longer lists under its kid is marked
+// StatusDeprecated; one newer still lists keeps the better of its two
+// statuses. Newer's keys come first. Neither ks nor newer is modified, so
+// the result can be swapped in while other goroutines read ks.
+func (ks *KeySet) MergeRetaining(newer *KeySet, graceKeys []string) *KeySet {
+	merged := NewKeySet()
+	merged.fetchedAt, merged.expiresAt = newer.fetchedAt, newer.expiresAt
+	for kid, entries := range newer.keys {
+		for _, e := range entries {
+			merged.AddWithStatus(kid, e.key, e.status)
+		}
+	}
+	for _, kid := range graceKeys {
+		for _, e := range ks.keys[kid] {
+			status := e.status
+			if _, listed := newer.KeyStatus(kid, e.key); !listed {
+				status = StatusDeprecated
+			}
+			merged.AddWithStatus(kid, e.key, status)
+		}
+	}
+	return merged
 }
 
 // IsExpired returns true if the key set has expired.
@@ -73,11 +205,123 @@ type FetchOptions struct {
 	// HTTPClient is the HTTP client to use.
 	HTTPClient *http.Client
 
-	// Timeout for the fetch operation.
+	// Timeout bounds the whole fetch operation, including every retry.
 	Timeout time.Duration
 
+	// AttemptTimeout bounds a single HTTP attempt, so a hung connection is
+	// abandoned and retried within Timeout. Zero means each attempt may use
+	// whatever remains of Timeout.
+	AttemptTimeout time.Duration
+
+	// Retries is the number of additional attempts made after a transport
+	// error, attempt timeout, or 5xx response. Zero disables retries.
+	Retries int
+
 	// MaxSize is the maximum response size in bytes.
 	MaxSize int64
+
+	// UserAgent is the User-Agent header sent with each request (default:
+	// "peac-go/<module version>", from the binary's build info).
+	UserAgent string
+
+	// Headers are added to each request, e.g. an API key for a JWKS origin
+	// behind auth. They replace the defaults for the same name, so a
+	// caller can override Accept (default: application/json); UserAgent,
+	// when set, takes precedence over a User-Agent entry here.
+	Headers http.Header
+
+	// SkipContentTypeCheck accepts a 200 response whatever its
+	// Content-Type, for lenient origins. By default only application/json
+	// and application/jwk-set+json are parsed; anything else (typically an
+	// HTML error page from a proxy) fails with ErrUnexpectedContentType.
+	SkipContentTypeCheck bool
+
+	// MaxRedirects caps the redirects followed per attempt. Zero (default)
+	// keeps HTTPClient's own policy (10 for http.DefaultClient); negative
+	// forbids redirects. Exceeding it fails with ErrRedirectNotAllowed.
+	MaxRedirects int
+
+	// SameOriginRedirects rejects redirects to a different scheme, host, or
+	// port than the JWKS URL with ErrRedirectNotAllowed. Off by default for
+	// compatibility, but recommended: a JWKS URL that redirects to another
+	// host lets that host choose which keys are trusted.
+	SameOriginRedirects bool
+}
+
+// ErrUnexpectedContentType is returned when a JWKS response is not JSON.
+var ErrUnexpectedContentType = errors.New("unexpected JWKS content type")
+
+// ErrRedirectNotAllowed is returned when a JWKS fetch is redirected beyond
+// FetchOptions.MaxRedirects or, with SameOriginRedirects, to another origin.
+var ErrRedirectNotAllowed = errors.New("JWKS redirect not allowed")
+
+// withRedirectPolicy returns client, or a copy of it enforcing
+// opts.MaxRedirects and opts.SameOriginRedirects before its own
+// CheckRedirect.
+func withRedirectPolicy(client *http.Client, opts FetchOptions) *http.Client {
+	if opts.MaxRedirects == 0 && !opts.SameOriginRedirects {
+		return client
+	}
+	limit := max(opts.MaxRedirects, 0)
+	limited := *client
+	next := client.CheckRedirect
+	limited.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if opts.MaxRedirects != 0 && len(via) > limit {
+			return fmt.Errorf("%w: more than %d redirects", ErrRedirectNotAllowed, limit)
+		}
+		if origin := via[0].URL; opts.SameOriginRedirects &&
+			(req.URL.Scheme != origin.Scheme || req.URL.Host != origin.Host) {
+			return fmt.Errorf("%w: cross-origin redirect to %s://%s", ErrRedirectNotAllowed, req.URL.Scheme, req.URL.Host)
+		}
+		if next != nil {
+			return next(req, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	return &limited
+}
+
+// isJSONContentType reports whether contentType is application/json or
+// application/jwk-set+json, ignoring parameters and case.
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || mediaType == "application/jwk-set+json"
+}
+
+// modulePath is this SDK's module path, used to find its version in the
+// build info.
+const modulePath = "github.com/peacprotocol/peac/sdks/go"
+
+// defaultUserAgent is the User-Agent sent when FetchOptions.UserAgent is
+// empty.
+var defaultUserAgent = userAgentFromBuildInfo()
+
+// userAgentFromBuildInfo derives "peac-go/<version>" from the module version
+// recorded in the binary, or "peac-go/devel" when it is unavailable (tests,
+// local replace directives).
+func userAgentFromBuildInfo() string {
+	version := ""
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Path == modulePath {
+			version = info.Main.Version
+		}
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath {
+				version = dep.Version
+			}
+		}
+	}
+	version = strings.TrimPrefix(version, "v")
+	if version == "" || version == "(devel)" {
+		version = "devel"
+	}
+	return "peac-go/" + version
 }
 
 // DefaultFetchOptions returns default fetch options.
@@ -89,7 +333,9 @@ func DefaultFetchOptions() FetchOptions {
 	}
 }
 
-// Fetch fetches a JWKS from a URL.
+// Fetch fetches a JWKS from a URL. The caller's ctx and opts.Timeout bound
+// the whole operation; opts.AttemptTimeout and opts.Retries control how a
+// slow or failing attempt is retried within that window.
 func Fetch(ctx context.Context, url string, opts FetchOptions) (*JWKS, error) {
 	if opts.HTTPClient == nil {
 		opts.HTTPClient = http.DefaultClient
@@ -100,75 +346,211 @@ func Fetch(ctx context.Context, url string, opts FetchOptions) (*JWKS, error) {
 	if opts.MaxSize == 0 {
 		opts.MaxSize = 1 << 20
 	}
+	opts.HTTPClient = withRedirectPolicy(opts.HTTPClient, opts)
 
 	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
 	defer cancel()
 
+	var lastErr error
+	for attempt := 0; attempt <= opts.Retries; attempt++ {
+		jwks, retryable, err := fetchAttempt(ctx, url, opts)
+		if err == nil {
+			return jwks, nil
+		}
+		lastErr = err
+		if !retryable || ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+// fetchAttempt performs a single HTTP fetch. retryable reports whether the
+// failure is transient (transport error, attempt timeout, or 5xx).
+func fetchAttempt(ctx context.Context, url string, opts FetchOptions) (_ *JWKS, retryable bool, _ error) {
+	if opts.AttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.AttemptTimeout)
+		defer cancel()
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "peac-go/0.9.25")
+	req.Header.Set("User-Agent", defaultUserAgent)
+	for name, values := range opts.Headers {
+		req.Header[http.CanonicalHeaderKey(name)] = slices.Clone(values)
+	}
+	if opts.UserAgent != "" {
+		req.Header.Set("User-Agent", opts.UserAgent)
+	}
 
 	resp, err := opts.HTTPClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+		return nil, !errors.Is(err, ErrRedirectNotAllowed), fmt.Errorf("failed to fetch JWKS: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, resp.StatusCode >= 500, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if !opts.SkipContentTypeCheck {
+		if ct := resp.Header.Get("Content-Type"); !isJSONContentType(ct) {
+			return nil, false, fmt.Errorf("%w: %q", ErrUnexpectedContentType, ct)
+		}
 	}
 
 	body, err := io.ReadAll(io.LimitReader(resp.Body, opts.MaxSize))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, true, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	var jwks JWKS
 	if err := json.Unmarshal(body, &jwks); err != nil {
-		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+		return nil, false, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	return &jwks, false, nil
+}
+
+// ErrNoSupportedKeys is returned when a JWKS yields no usable Ed25519 keys.
+var ErrNoSupportedKeys = errors.New("JWKS contained no Ed25519 keys")
+
+// SkippedKey records a JWK that was not added to a KeySet, and why.
+type SkippedKey struct {
+	KeyID  string
+	Reason string
+}
+
+// KeySetError describes JWKs that could not be converted. It wraps
+// ErrNoSupportedKeys when no usable key remained.
+type KeySetError struct {
+	Skipped []SkippedKey
+	empty   bool
+}
+
+func (e *KeySetError) Error() string {
+	var b strings.Builder
+	if e.empty {
+		b.WriteString(ErrNoSupportedKeys.Error())
+	} else {
+		b.WriteString("JWKS contained unsupported keys")
 	}
+	for i, k := range e.Skipped {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		fmt.Fprintf(&b, "kid %q: %s", k.KeyID, k.Reason)
+	}
+	return b.String()
+}
 
-	return &jwks, nil
+func (e *KeySetError) Unwrap() error {
+	if e.empty {
+		return ErrNoSupportedKeys
+	}
+	return nil
 }
 
-// ToKeySet converts a JWKS to a KeySet, extracting Ed25519 keys.
+// ToKeySet converts a JWKS to a KeySet, extracting Ed25519 keys. Revoked keys
+// are dropped; deprecated keys are kept for verification and reported by
+// KeySet.Status. Unsupported or malformed keys are skipped silently; use
+// ToKeySetStrict to see why.
 func (j *JWKS) ToKeySet() (*KeySet, error) {
+	ks, _ := j.toKeySet()
+	return ks, nil
+}
+
+// ToKeySetStrict is ToKeySet, but returns a *KeySetError listing every key
+// skipped for a wrong kty/crv, bad base64 or wrong size. Revoked keys are
+// dropped without error. The KeySet is returned alongside the error so
+// callers may still use the supported keys.
+func (j *JWKS) ToKeySetStrict() (*KeySet, error) {
+	ks, skipped := j.toKeySet()
+	if len(skipped) > 0 || len(ks.keys) == 0 {
+		return ks, &KeySetError{Skipped: skipped, empty: len(ks.keys) == 0}
+	}
+	return ks, nil
+}
+
+func (j *JWKS) toKeySet() (*KeySet, []SkippedKey) {
 	ks := NewKeySet()
 	ks.fetchedAt = time.Now()
 	ks.expiresAt = time.Now().Add(5 * time.Minute)
 
+	var skipped []SkippedKey
 	for _, jwk := range j.Keys {
 		if jwk.KeyType != "OKP" || jwk.Curve != "Ed25519" {
+			skipped = append(skipped, SkippedKey{jwk.KeyID, fmt.Sprintf("unsupported key type kty=%q crv=%q", jwk.KeyType, jwk.Curve)})
 			continue
 		}
 
-		// Skip revoked keys
-		if jwk.Status == "revoked" {
+		// Skip revoked keys; deprecated keys stay verify-only
+		if jwk.Status == StatusRevoked {
 			continue
 		}
 
 		keyBytes, err := base64.RawURLEncoding.DecodeString(jwk.X)
 		if err != nil {
+			skipped = append(skipped, SkippedKey{jwk.KeyID, "x is not valid base64url"})
 			continue
 		}
 
 		if len(keyBytes) != ed25519.PublicKeySize {
+			skipped = append(skipped, SkippedKey{jwk.KeyID, fmt.Sprintf("x is %d bytes, want %d", len(keyBytes), ed25519.PublicKeySize)})
 			continue
 		}
 
-		ks.Add(jwk.KeyID, ed25519.PublicKey(keyBytes))
+		ks.AddWithStatus(jwk.KeyID, ed25519.PublicKey(keyBytes), jwk.Status)
 	}
 
-	return ks, nil
+	return ks, skipped
+}
+
+// Thumbprint returns the RFC 7638 JWK thumbprint of an Ed25519 public key:
+// the base64url (unpadded) SHA-256 of its canonical OKP JWK.
+func Thumbprint(key ed25519.PublicKey) string {
+	canonical := `{"crv":"Ed25519","kty":"OKP","x":"` + base64.RawURLEncoding.EncodeToString(key) + `"}`
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
 }
 
-// DiscoverJWKS discovers the JWKS URL from an issuer URL.
+// DefaultJWKSPath is the well-known path DiscoverJWKS uses.
+const DefaultJWKSPath = "/.well-known/jwks.json"
+
+// DiscoverJWKS discovers the JWKS URL from an issuer URL, at DefaultJWKSPath
+// under the issuer's origin. Issuers DiscoverJWKSPath rejects (e.g. plain
+// http in tests) get DefaultJWKSPath appended as-is.
 func DiscoverJWKS(issuer string) string {
-	// Standard well-known path
-	issuer = strings.TrimSuffix(issuer, "/")
-	return issuer + "/.well-known/jwks.json"
+	if jwksURL, err := DiscoverJWKSPath(issuer, DefaultJWKSPath); err == nil {
+		return jwksURL
+	}
+	return strings.TrimSuffix(issuer, "/") + DefaultJWKSPath
+}
+
+// DiscoverJWKSPath returns the JWKS URL at path under the issuer's origin,
+// for issuers that host keys somewhere other than DefaultJWKSPath. issuer
+// must be an absolute https URL with a host and no userinfo; any path,
+// query, or fragment on it is dropped. path is cleaned and made absolute,
+// so "keys//jwks.json" becomes "/keys/jwks.json"; an empty path means
+// DefaultJWKSPath.
+func DiscoverJWKSPath(issuer, jwksPath string) (string, error) {
+	u, err := url.Parse(issuer)
+	if err != nil {
+		return "", fmt.Errorf("invalid issuer URL: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return "", fmt.Errorf("issuer must be an https URL with a host, got %q", issuer)
+	}
+	if jwksPath == "" {
+		jwksPath = DefaultJWKSPath
+	}
+	origin := url.URL{Scheme: u.Scheme, Host: u.Host, Path: path.Clean("/" + jwksPath)}
+	return origin.String(), nil
 }
@@ -0,0 +1,463 @@
+package jwks
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetch_RetriesAfterAttemptTimeout(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(nil)
+	body, _ := json.Marshal(JWKS{Keys: []JWK{{
+		KeyType: "OKP",
+		Curve:   "Ed25519",
+		KeyID:   "k1",
+		X:       base64.RawURLEncoding.EncodeToString(pub),
+	}}})
+
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if attempts.Add(1) == 1 {
+			// First attempt hangs past the per-attempt timeout.
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	opts := DefaultFetchOptions()
+	opts.Timeout = 2 * time.Second
+	opts.AttemptTimeout = 50 * time.Millisecond
+	opts.Retries = 1
+
+	jwks, err := Fetch(context.Background(), srv.URL, opts)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(jwks.Keys) != 1 || jwks.Keys[0].KeyID != "k1" {
+		t.Errorf("unexpected keys: %+v", jwks.Keys)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
+
+func TestFetch_Headers(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		got = r.Header.Clone()
+		_, _ = w.Write([]byte(`{"keys":[]}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name      string
+		userAgent string
+		headers   http.Header
+		wantUA    string
+		wantAcc   string
+		wantKey   string
+	}{
+		{"defaults", "", nil, defaultUserAgent, "application/json", ""},
+		{"custom headers", "", http.Header{"x-api-key": {"secret"}}, defaultUserAgent, "application/json", "secret"},
+		{"override accept", "", http.Header{"Accept": {"application/jwk-set+json"}}, defaultUserAgent, "application/jwk-set+json", ""},
+		{"user agent wins", "acme/1.0", http.Header{"User-Agent": {"other"}}, "acme/1.0", "application/json", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultFetchOptions()
+			opts.UserAgent = tt.userAgent
+			opts.Headers = tt.headers
+			if _, err := Fetch(context.Background(), srv.URL, opts); err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if ua := got.Get("User-Agent"); ua != tt.wantUA {
+				t.Errorf("User-Agent = %q, want %q", ua, tt.wantUA)
+			}
+			if acc := got.Get("Accept"); acc != tt.wantAcc {
+				t.Errorf("Accept = %q, want %q", acc, tt.wantAcc)
+			}
+			if key := got.Get("X-Api-Key"); key != tt.wantKey {
+				t.Errorf("X-Api-Key = %q, want %q", key, tt.wantKey)
+			}
+		})
+	}
+	if !strings.HasPrefix(defaultUserAgent, "peac-go/") {
+		t.Errorf("defaultUserAgent = %q, want peac-go/ prefix", defaultUserAgent)
+	}
+}
+
+func TestFetch_ContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		skip        bool
+		wantErr     bool
+	}{
+		{"application/json", false, false},
+		{"application/json; charset=utf-8", false, false},
+		{"application/jwk-set+json", false, false},
+		{"text/html; charset=utf-8", false, true},
+		{"", false, true},
+		{"text/html", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header()["Content-Type"] = []string{tt.contentType}
+				_, _ = w.Write([]byte(`{"keys":[]}`))
+			}))
+			defer srv.Close()
+
+			opts := DefaultFetchOptions()
+			opts.SkipContentTypeCheck = tt.skip
+			_, err := Fetch(context.Background(), srv.URL, opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrUnexpectedContentType) {
+				t.Errorf("Fetch() error = %v, want ErrUnexpectedContentType", err)
+			}
+		})
+	}
+}
+
+func TestFetch_RedirectPolicy(t *testing.T) {
+	serveJWKS := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"keys":[]}`))
+	}
+	other := httptest.NewServer(http.HandlerFunc(serveJWKS))
+	defer other.Close()
+
+	var hits atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jwks", serveJWKS)
+	mux.HandleFunc("/hop", func(w http.ResponseWriter, r *http.Request) { http.Redirect(w, r, "/jwks", http.StatusFound) })
+	mux.HandleFunc("/hop2", func(w http.ResponseWriter, r *http.Request) { http.Redirect(w, r, "/hop", http.StatusFound) })
+	mux.HandleFunc("/away", func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		http.Redirect(w, r, other.URL+"/jwks", http.StatusMovedPermanently)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	tests := []struct {
+		name         string
+		path         string
+		maxRedirects int
+		sameOrigin   bool
+		wantErr      bool
+	}{
+		{"default follows cross-origin", "/away", 0, false, false},
+		{"same-origin allowed", "/hop", 0, true, false},
+		{"cross-origin rejected", "/away", 0, true, true},
+		{"redirects forbidden", "/hop", -1, false, true},
+		{"within limit", "/hop", 1, false, false},
+		{"over limit", "/hop2", 1, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultFetchOptions()
+			opts.MaxRedirects = tt.maxRedirects
+			opts.SameOriginRedirects = tt.sameOrigin
+			_, err := Fetch(context.Background(), srv.URL+tt.path, opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrRedirectNotAllowed) {
+				t.Errorf("Fetch() error = %v, want ErrRedirectNotAllowed", err)
+			}
+		})
+	}
+
+	// A rejected redirect is not retried.
+	hits.Store(0)
+	opts := DefaultFetchOptions()
+	opts.SameOriginRedirects = true
+	opts.Retries = 2
+	if _, err := Fetch(context.Background(), srv.URL+"/away", opts); !errors.Is(err, ErrRedirectNotAllowed) {
+		t.Fatalf("Fetch() error = %v, want ErrRedirectNotAllowed", err)
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("redirecting origin hit %d times, want 1", n)
+	}
+}
+
+func TestFetch_OverallTimeoutBoundsRetries(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	opts := DefaultFetchOptions()
+	opts.Timeout = 100 * time.Millisecond
+	opts.AttemptTimeout = 40 * time.Millisecond
+	opts.Retries = 100
+
+	start := time.Now()
+	if _, err := Fetch(context.Background(), srv.URL, opts); err == nil {
+		t.Fatal("expected error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Fetch() took %s, want it bounded by Timeout", elapsed)
+	}
+}
+
+func TestFetch_DoesNotRetryClientErrors(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		attempts.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	opts := DefaultFetchOptions()
+	opts.Retries = 3
+	if _, err := Fetch(context.Background(), srv.URL, opts); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestToKeySet_Status(t *testing.T) {
+	jwk := func(kid, status string) JWK {
+		pub, _, _ := ed25519.GenerateKey(nil)
+		return JWK{KeyType: "OKP", Curve: "Ed25519", KeyID: kid, X: base64.RawURLEncoding.EncodeToString(pub), Status: status}
+	}
+	set := &JWKS{Keys: []JWK{
+		jwk("current", ""),
+		jwk("explicit", StatusActive),
+		jwk("old", StatusDeprecated),
+		jwk("compromised", StatusRevoked),
+	}}
+
+	ks, err := set.ToKeySet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		kid    string
+		status string
+		found  bool
+	}{
+		{"current", StatusActive, true},
+		{"explicit", StatusActive, true},
+		{"old", StatusDeprecated, true},
+		{"compromised", "", false},
+	}
+	for _, tt := range tests {
+		if _, ok := ks.Get(tt.kid); ok != tt.found {
+			t.Errorf("Get(%q) found = %v, want %v", tt.kid, ok, tt.found)
+		}
+		status, ok := ks.Status(tt.kid)
+		if ok != tt.found || status != tt.status {
+			t.Errorf("Status(%q) = %q, %v, want %q, %v", tt.kid, status, ok, tt.status, tt.found)
+		}
+	}
+}
+
+func TestToKeySet_DuplicateKid(t *testing.T) {
+	old, _, _ := ed25519.GenerateKey(nil)
+	next, _, _ := ed25519.GenerateKey(nil)
+	jwk := func(pub ed25519.PublicKey, status string) JWK {
+		return JWK{KeyType: "OKP", Curve: "Ed25519", KeyID: "k1", X: base64.RawURLEncoding.EncodeToString(pub), Status: status}
+	}
+	set := &JWKS{Keys: []JWK{jwk(old, StatusDeprecated), jwk(next, ""), jwk(old, StatusDeprecated)}}
+
+	ks, err := set.ToKeySet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidates := ks.Candidates("k1")
+	if len(candidates) != 2 || !candidates[0].Equal(old) || !candidates[1].Equal(next) {
+		t.Errorf("Candidates(k1) = %d keys, want [old next]", len(candidates))
+	}
+	if key, _ := ks.Get("k1"); !key.Equal(old) {
+		t.Error("Get(k1) should return the first candidate")
+	}
+	if status, _ := ks.Status("k1"); status != StatusActive {
+		t.Errorf("Status(k1) = %q, want %q while any candidate is active", status, StatusActive)
+	}
+	if status, _ := ks.KeyStatus("k1", old); status != StatusDeprecated {
+		t.Errorf("KeyStatus(k1, old) = %q, want %q", status, StatusDeprecated)
+	}
+	if status, _ := ks.KeyStatus("k1", next); status != StatusActive {
+		t.Errorf("KeyStatus(k1, next) = %q, want %q", status, StatusActive)
+	}
+	if _, ok := ks.KeyStatus("k1", ed25519.PublicKey(make([]byte, ed25519.PublicKeySize))); ok {
+		t.Error("KeyStatus() should report false for a key not under the kid")
+	}
+	if ks.Len() != 1 || ks.Candidates("missing") != nil {
+		t.Errorf("Len() = %d, Candidates(missing) = %v", ks.Len(), ks.Candidates("missing"))
+	}
+}
+
+func TestKeySet_MergeRetaining(t *testing.T) {
+	a, _, _ := ed25519.GenerateKey(nil)
+	b, _, _ := ed25519.GenerateKey(nil)
+	c, _, _ := ed25519.GenerateKey(nil)
+	b2, _, _ := ed25519.GenerateKey(nil)
+
+	old := NewKeySet()
+	old.Add("a", a)
+	old.Add("b", b)
+	old.Add("c", c)
+	newer := NewKeySet()
+	newer.Add("b", b2)
+	newer.Add("d", c)
+
+	merged := old.MergeRetaining(newer, []string{"a", "b", "missing"})
+	if got := merged.KeyIDs(); !slices.Equal(got, []string{"a", "b", "d"}) {
+		t.Fatalf("KeyIDs() = %v, want [a b d]", got)
+	}
+	if status, _ := merged.Status("a"); status != StatusDeprecated {
+		t.Errorf("Status(a) = %q, want %q for a retained dropped key", status, StatusDeprecated)
+	}
+	candidates := merged.Candidates("b")
+	if len(candidates) != 2 || !candidates[0].Equal(b2) || !candidates[1].Equal(b) {
+		t.Errorf("Candidates(b) = %d keys, want [newer old]", len(candidates))
+	}
+	if status, _ := merged.Status("b"); status != StatusActive {
+		t.Errorf("Status(b) = %q, want %q", status, StatusActive)
+	}
+	if status, _ := merged.KeyStatus("b", b); status != StatusDeprecated {
+		t.Errorf("KeyStatus(b, old key) = %q, want %q once newer drops it", status, StatusDeprecated)
+	}
+	if old.Len() != 3 || newer.Len() != 2 {
+		t.Errorf("inputs modified: old.Len() = %d, newer.Len() = %d", old.Len(), newer.Len())
+	}
+}
+
+func TestKeySet_KeyIDs(t *testing.T) {
+	ks := NewKeySet()
+	if ks.Len() != 0 || len(ks.KeyIDs()) != 0 {
+		t.Fatalf("empty set: Len = %d, KeyIDs = %v", ks.Len(), ks.KeyIDs())
+	}
+	pub, _, _ := ed25519.GenerateKey(nil)
+	for _, kid := range []string{"b", "c", "a"} {
+		ks.Add(kid, pub)
+	}
+	if got := ks.KeyIDs(); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("KeyIDs() = %v, want [a b c]", got)
+	}
+	if ks.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", ks.Len())
+	}
+}
+
+func TestToKeySetStrict(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(nil)
+	good := JWK{KeyType: "OKP", Curve: "Ed25519", KeyID: "good", X: base64.RawURLEncoding.EncodeToString(pub)}
+	rsa := JWK{KeyType: "RSA", KeyID: "rsa-1", N: "AQAB", E: "AQAB"}
+	badB64 := JWK{KeyType: "OKP", Curve: "Ed25519", KeyID: "bad-b64", X: "!!!"}
+	short := JWK{KeyType: "OKP", Curve: "Ed25519", KeyID: "short", X: base64.RawURLEncoding.EncodeToString(pub[:16])}
+	revoked := JWK{KeyType: "OKP", Curve: "Ed25519", KeyID: "revoked", X: good.X, Status: StatusRevoked}
+
+	ks, err := (&JWKS{Keys: []JWK{good, revoked}}).ToKeySetStrict()
+	if err != nil {
+		t.Fatalf("ToKeySetStrict() error = %v", err)
+	}
+	if _, ok := ks.Get("good"); !ok {
+		t.Error("expected good key")
+	}
+
+	ks, err = (&JWKS{Keys: []JWK{good, rsa}}).ToKeySetStrict()
+	var ksErr *KeySetError
+	if !errors.As(err, &ksErr) || errors.Is(err, ErrNoSupportedKeys) {
+		t.Fatalf("mixed set error = %v, want KeySetError without ErrNoSupportedKeys", err)
+	}
+	if _, ok := ks.Get("good"); !ok {
+		t.Error("supported keys should still be returned")
+	}
+
+	_, err = (&JWKS{Keys: []JWK{rsa, badB64, short}}).ToKeySetStrict()
+	if !errors.Is(err, ErrNoSupportedKeys) {
+		t.Fatalf("error = %v, want ErrNoSupportedKeys", err)
+	}
+	for _, want := range []string{`"rsa-1": unsupported key type`, `"bad-b64": x is not valid base64url`, `"short": x is 16 bytes`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+}
+
+func TestCache_NoSupportedKeys(t *testing.T) {
+	body, _ := json.Marshal(JWKS{Keys: []JWK{{KeyType: "RSA", KeyID: "rsa-1", N: "AQAB", E: "AQAB"}}})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	_, err := NewCache(DefaultCacheOptions()).Get(context.Background(), srv.URL)
+	if !errors.Is(err, ErrNoSupportedKeys) {
+		t.Errorf("Get() error = %v, want ErrNoSupportedKeys", err)
+	}
+}
+
+func TestDiscoverJWKSPath(t *testing.T) {
+	tests := []struct {
+		issuer  string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"https://issuer.example", "/keys/jwks.json", "https://issuer.example/keys/jwks.json", false},
+		{"https://issuer.example/", "keys//jwks.json", "https://issuer.example/keys/jwks.json", false},
+		{"https://issuer.example/tenant?x=1", "/jwks", "https://issuer.example/jwks", false},
+		{"https://issuer.example:8443", "", "https://issuer.example:8443/.well-known/jwks.json", false},
+		{"http://issuer.example", "/jwks", "", true},
+		{"https://user@issuer.example", "/jwks", "", true},
+		{"issuer.example", "/jwks", "", true},
+		{"did:web:issuer.example", "/jwks", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.issuer+tt.path, func(t *testing.T) {
+			got, err := DiscoverJWKSPath(tt.issuer, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DiscoverJWKSPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DiscoverJWKSPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := DiscoverJWKS("https://issuer.example/"); got != "https://issuer.example/.well-known/jwks.json" {
+		t.Errorf("DiscoverJWKS() = %q", got)
+	}
+	if got := DiscoverJWKS("http://127.0.0.1:8080"); got != "http://127.0.0.1:8080/.well-known/jwks.json" {
+		t.Errorf("DiscoverJWKS(http) = %q", got)
+	}
+}
+
+func TestThumbprint(t *testing.T) {
+	// RFC 8037 Appendix A.3
+	x, err := base64.RawURLEncoding.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := Thumbprint(ed25519.PublicKey(x)), "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"; got != want {
+		t.Errorf("Thumbprint() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+// Package jwkstest serves JWKS documents for tests that verify records
+// against a live key endpoint.
+package jwkstest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/peacprotocol/peac/sdks/go/jwks"
+	"github.com/peacprotocol/peac/sdks/go/jws"
+)
+
+// NewServer starts an httptest server publishing the public halves of keys
+// as a JWKS at jwks.DefaultJWKSPath, and returns it with that URL. The URL
+// is what jwks.DiscoverJWKS returns for the server's base URL, so the server
+// can stand in for an issuer. Other paths return 404. The caller must Close
+// the server.
+func NewServer(keys ...*jws.SigningKey) (*httptest.Server, string) {
+	set := jwks.JWKS{Keys: make([]jwks.JWK, len(keys))}
+	for i, key := range keys {
+		set.Keys[i] = key.JWK("", time.Time{})
+	}
+	body, err := json.Marshal(set)
+	if err != nil {
+		panic("jwkstest: marshal JWKS: " + err.Error())
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+jwks.DefaultJWKSPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	})
+	srv := httptest.NewServer(mux)
+	return srv, srv.URL + jwks.DefaultJWKSPath
+}
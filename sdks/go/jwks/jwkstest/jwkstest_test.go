@@ -0,0 +1,43 @@
+package jwkstest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/peacprotocol/peac/sdks/go/jwks"
+	"github.com/peacprotocol/peac/sdks/go/jws"
+)
+
+func TestNewServer(t *testing.T) {
+	keyA, _ := jws.GenerateSigningKey("a-1")
+	keyB, _ := jws.GenerateSigningKey("b-1")
+	srv, url := NewServer(keyA, keyB)
+	defer srv.Close()
+
+	if got := jwks.DiscoverJWKS(srv.URL); got != url {
+		t.Errorf("DiscoverJWKS() = %q, want %q", got, url)
+	}
+	set, err := jwks.Fetch(context.Background(), url, jwks.DefaultFetchOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	keySet, err := set.ToKeySet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []*jws.SigningKey{keyA, keyB} {
+		if got, ok := keySet.Get(key.KeyID()); !ok || !key.PublicKey().Equal(got) {
+			t.Errorf("key %s not served", key.KeyID())
+		}
+	}
+
+	resp, err := http.Get(srv.URL + "/other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /other = %d, want 404", resp.StatusCode)
+	}
+}
@@ -0,0 +1,102 @@
+package jwks
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Registry resolution errors.
+var (
+	// ErrUnknownIssuer is returned when an issuer has not been added.
+	ErrUnknownIssuer = errors.New("issuer not registered")
+
+	// ErrKeyNotFound is returned when the issuer's key set has no such kid.
+	ErrKeyNotFound = errors.New("key not found")
+)
+
+// registrySource is where an issuer's keys come from: a JWKS URL resolved
+// through the cache, or a static key set.
+type registrySource struct {
+	url    string
+	keySet *KeySet
+}
+
+// Registry maps issuers to their key sources for multi-tenant verifiers.
+// JWKS URLs are fetched and cached through a shared Cache. It is safe for
+// concurrent use.
+//
+// Only registered issuers resolve; an unregistered iss is never discovered
+// on the fly, so a record cannot point the verifier at arbitrary hosts.
+type Registry struct {
+	mu      sync.RWMutex
+	sources map[string]registrySource
+	cache   *Cache
+}
+
+// NewRegistry creates an empty registry. If cache is nil, a cache with
+// DefaultCacheOptions is used.
+func NewRegistry(cache *Cache) *Registry {
+	if cache == nil {
+		cache = NewCache(DefaultCacheOptions())
+	}
+	return &Registry{
+		sources: make(map[string]registrySource),
+		cache:   cache,
+	}
+}
+
+// Add registers issuer with the JWKS at jwksURL. If jwksURL is empty, it is
+// discovered from the issuer via DiscoverJWKS.
+func (r *Registry) Add(issuer, jwksURL string) {
+	if jwksURL == "" {
+		jwksURL = DiscoverJWKS(issuer)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sources[issuer] = registrySource{url: jwksURL}
+}
+
+// AddKeySet registers issuer with a static key set. No fetch is made.
+func (r *Registry) AddKeySet(issuer string, keySet *KeySet) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sources[issuer] = registrySource{keySet: keySet}
+}
+
+// Remove unregisters issuer. Cached JWKS entries are left to expire.
+func (r *Registry) Remove(issuer string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sources, issuer)
+}
+
+// KeySet returns the key set for issuer, fetching through the cache when the
+// issuer is registered by URL.
+func (r *Registry) KeySet(ctx context.Context, issuer string) (*KeySet, error) {
+	r.mu.RLock()
+	src, ok := r.sources[issuer]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownIssuer, issuer)
+	}
+	if src.keySet != nil {
+		return src.keySet, nil
+	}
+	return r.cache.Get(ctx, src.url)
+}
+
+// Resolve returns the public key for kid in issuer's key set.
+func (r *Registry) Resolve(ctx context.Context, issuer, kid string) (ed25519.PublicKey, error) {
+	keySet, err := r.KeySet(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := keySet.Get(kid)
+	if !ok {
+		return nil, fmt.Errorf("%w: kid %q for issuer %s", ErrKeyNotFound, kid, issuer)
+	}
+	return key, nil
+}
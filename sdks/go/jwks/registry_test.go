@@ -0,0 +1,47 @@
+package jwks
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+)
+
+func TestRegistry_ResolveByIssuer(t *testing.T) {
+	pubA, _, _ := ed25519.GenerateKey(nil)
+	pubB, _, _ := ed25519.GenerateKey(nil)
+	srv := jwksServer(t, 0, pubB)
+
+	registry := NewRegistry(nil)
+	staticSet := NewKeySet()
+	staticSet.Add("a1", pubA)
+	registry.AddKeySet("https://a.example", staticSet)
+	registry.Add("https://b.example", srv.URL)
+
+	ctx := context.Background()
+	if key, err := registry.Resolve(ctx, "https://a.example", "a1"); err != nil || !key.Equal(pubA) {
+		t.Errorf("Resolve(a) = %v, %v", key, err)
+	}
+	if key, err := registry.Resolve(ctx, "https://b.example", "fresh"); err != nil || !key.Equal(pubB) {
+		t.Errorf("Resolve(b) = %v, %v", key, err)
+	}
+	if _, err := registry.Resolve(ctx, "https://a.example", "fresh"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("cross-issuer kid err = %v, want ErrKeyNotFound", err)
+	}
+	if _, err := registry.Resolve(ctx, "https://c.example", "a1"); !errors.Is(err, ErrUnknownIssuer) {
+		t.Errorf("unregistered issuer err = %v, want ErrUnknownIssuer", err)
+	}
+
+	registry.Remove("https://a.example")
+	if _, err := registry.KeySet(ctx, "https://a.example"); !errors.Is(err, ErrUnknownIssuer) {
+		t.Errorf("removed issuer err = %v, want ErrUnknownIssuer", err)
+	}
+}
+
+func TestRegistry_AddDiscoversURL(t *testing.T) {
+	registry := NewRegistry(nil)
+	registry.Add("https://issuer.example/", "")
+	if got := registry.sources["https://issuer.example/"].url; got != "https://issuer.example/.well-known/jwks.json" {
+		t.Errorf("discovered URL = %q", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"strings"
 )
 
 // Ed25519PublicKey represents an Ed25519 public key.
@@ -98,8 +99,15 @@ func VerifyEd25519(publicKey ed25519.PublicKey, message, signature []byte) error
 	return nil
 }
 
-// VerifyJWS verifies a JWS using Ed25519.
+// VerifyJWS verifies a JWS using Ed25519. An alg "none" header or an empty
+// signature fails with ErrAlgNone or ErrEmptySignature.
 func VerifyJWS(jws *ParsedJWS, publicKey ed25519.PublicKey) error {
+	if strings.EqualFold(jws.Header.Algorithm, "none") {
+		return ErrAlgNone
+	}
+	if len(jws.Signature) == 0 {
+		return ErrEmptySignature
+	}
 	if jws.Header.Algorithm != "EdDSA" {
 		return fmt.Errorf("unsupported algorithm: %s", jws.Header.Algorithm)
 	}
@@ -0,0 +1,61 @@
+package jws
+
+import (
+	"strings"
+	"testing"
+)
+
+// FuzzParse tests that Parse never panics on arbitrary input and that a
+// successful parse is internally consistent.
+// Run with: go test -fuzz=FuzzParse -fuzztime=30s ./jws
+func FuzzParse(f *testing.F) {
+	key, err := GenerateSigningKey("fuzz-key")
+	if err != nil {
+		f.Fatal(err)
+	}
+	valid, err := key.SignWithType([]byte(`{"iss":"https://example.com"}`), InteractionRecordTyp)
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	seeds := []string{
+		valid,
+		"",
+		".",
+		"..",
+		"...",
+		"a.b.c",
+		"e30.e30.",
+		"e30..",
+		"!!!.@@@.###",
+		"eyJhbGciOiJFZERTQSJ9.e30.AA",
+		"eyJ.e30.AA",
+		"bnVsbA.bnVsbA.",
+		"W10.W10.W10",
+		strings.Repeat(".", 64),
+		strings.Repeat("A", 9000) + ".e30.AA",
+		"e30.e30." + strings.Repeat("A", 2000),
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, compact string) {
+		parsed, err := Parse(compact)
+		if err != nil {
+			if parsed != nil {
+				t.Fatal("Parse returned both a result and an error")
+			}
+			return
+		}
+		if parsed.CompactSerialization != compact {
+			t.Fatal("CompactSerialization does not match input")
+		}
+		if len(parsed.HeaderRaw) > maxEncodedHeaderBytes || len(parsed.Signature) > maxEncodedSignatureBytes {
+			t.Fatal("decoded part exceeds its bound")
+		}
+		if !strings.HasPrefix(compact, string(parsed.SigningInput)) {
+			t.Fatal("SigningInput is not a prefix of the input")
+		}
+	})
+}
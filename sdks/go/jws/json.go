@@ -0,0 +1,74 @@
+package jws
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// FlattenedJWS is the RFC 7515 Section 7.2.2 flattened JSON serialization.
+// All members hold base64url (unpadded) encoded values.
+type FlattenedJWS struct {
+	Protected string `json:"protected"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// SignJSON creates a flattened JSON JWS serialization for the given payload.
+// The typ header is set to DefaultReceiptTyp. Compact serialization (Sign)
+// remains the default everywhere; this exists for JSON-JWS interop.
+func (k *SigningKey) SignJSON(payload []byte) ([]byte, error) {
+	return k.SignJSONWithType(payload, DefaultReceiptTyp)
+}
+
+// SignJSONWithType creates a flattened JSON JWS serialization with a custom
+// type header.
+func (k *SigningKey) SignJSONWithType(payload []byte, typ string) ([]byte, error) {
+	compact, err := k.SignWithType(payload, typ)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := Parse(compact)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(parsed.Flattened())
+}
+
+// Flattened returns the flattened JSON serialization members of the JWS.
+func (p *ParsedJWS) Flattened() FlattenedJWS {
+	return FlattenedJWS{
+		Protected: Encode(p.HeaderRaw),
+		Payload:   Encode(p.Payload),
+		Signature: Encode(p.Signature),
+	}
+}
+
+// ParseJSON parses an RFC 7515 flattened JSON JWS serialization.
+//
+// The unprotected "header" member is rejected: every header parameter must be
+// integrity-protected. The returned ParsedJWS carries the equivalent compact
+// serialization in CompactSerialization, so it verifies exactly like a
+// compact JWS.
+func ParseJSON(data []byte) (*ParsedJWS, error) {
+	var raw struct {
+		FlattenedJWS
+		Header     json.RawMessage `json:"header"`
+		Signatures json.RawMessage `json:"signatures"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON JWS: %w", err)
+	}
+	if raw.Signatures != nil {
+		return nil, fmt.Errorf("general JSON serialization is not supported; use flattened form")
+	}
+	if raw.Header != nil {
+		return nil, fmt.Errorf("unprotected header is not allowed")
+	}
+	if raw.Protected == "" {
+		return nil, fmt.Errorf("missing protected header")
+	}
+	if raw.Signature == "" {
+		return nil, fmt.Errorf("missing signature")
+	}
+	return Parse(raw.Protected + "." + raw.Payload + "." + raw.Signature)
+}
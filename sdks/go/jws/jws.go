@@ -4,10 +4,38 @@ package jws
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 )
 
+// Unsecured-JWS rejections. A header with alg "none" and an empty signature
+// is the classic way to present an unsigned token as a signed one; both are
+// rejected with these distinct errors rather than a generic algorithm
+// mismatch, so logs and callers can tell an attack from a misconfiguration.
+var (
+	ErrAlgNone        = errors.New(`unsecured JWS rejected: alg "none" is never accepted`)
+	ErrEmptySignature = errors.New("unsecured JWS rejected: signature is empty")
+)
+
+// ErrPaddingPresent reports a JWS part encoded as padded base64url. Compact
+// JWS forbids padding; set ParseOptions.AllowPadding to accept it from lax
+// encoders.
+var ErrPaddingPresent = errors.New("base64url padding present")
+
+// ParseOptions configures ParseWithOptions.
+type ParseOptions struct {
+	// MaxBytes rejects input longer than this before decoding anything, as
+	// ParseWithLimit does. Zero or negative disables the limit.
+	MaxBytes int
+
+	// AllowPadding accepts parts encoded as padded base64url ("=" suffix),
+	// as produced by some non-conforming implementations. The signature is
+	// still checked over the parts exactly as received. Off by default:
+	// padded parts fail with ErrPaddingPresent.
+	AllowPadding bool
+}
+
 // Header represents a JWS header.
 type Header struct {
 	Algorithm   string `json:"alg"`
@@ -26,14 +54,59 @@ type ParsedJWS struct {
 	CompactSerialization string
 }
 
+// DefaultMaxJWSBytes is a suggested cap for ParseWithLimit on untrusted
+// input such as request headers (64 KiB).
+const DefaultMaxJWSBytes = 64 << 10
+
+// Per-part bounds applied by Parse before decoding. An Ed25519 signature
+// encodes to 86 bytes and a protected header to well under 1 KiB.
+const (
+	maxEncodedHeaderBytes    = 8 << 10
+	maxEncodedSignatureBytes = 1 << 10
+)
+
 // Parse parses a JWS compact serialization.
+//
+// Parse never panics on malformed input (see FuzzParse). It bounds the header
+// and signature parts, but not the payload: an attacker-supplied
+// multi-megabyte payload is decoded before any validation. Use
+// ParseWithLimit for untrusted input.
 func Parse(compact string) (*ParsedJWS, error) {
-	parts := strings.Split(compact, ".")
-	if len(parts) != 3 {
-		return nil, fmt.Errorf("invalid JWS format: expected 3 parts, got %d", len(parts))
+	return ParseWithOptions(compact, ParseOptions{})
+}
+
+// ParseWithLimit parses a JWS compact serialization, rejecting input longer
+// than maxBytes before decoding anything. base64url decoding only shrinks
+// data, so each decoded part is bounded by maxBytes as well. A maxBytes of
+// zero or less disables the limit.
+func ParseWithLimit(compact string, maxBytes int) (*ParsedJWS, error) {
+	return ParseWithOptions(compact, ParseOptions{MaxBytes: maxBytes})
+}
+
+// ParseWithOptions parses a JWS compact serialization like Parse, applying
+// opts.
+func ParseWithOptions(compact string, opts ParseOptions) (*ParsedJWS, error) {
+	if opts.MaxBytes > 0 && len(compact) > opts.MaxBytes {
+		return nil, fmt.Errorf("JWS too large: %d bytes exceeds limit of %d bytes", len(compact), opts.MaxBytes)
+	}
+
+	// Count separators first so a string of many dots cannot force a large
+	// slice allocation before it is rejected.
+	if n := strings.Count(compact, ".") + 1; n != 3 {
+		return nil, fmt.Errorf("invalid JWS format: expected 3 parts, got %d", n)
+	}
+	parts := strings.SplitN(compact, ".", 3)
+
+	// Header and signature have small legitimate sizes; reject absurd
+	// lengths before decoding them.
+	if len(parts[0]) > maxEncodedHeaderBytes {
+		return nil, fmt.Errorf("JWS header too large: %d bytes exceeds limit of %d bytes", len(parts[0]), maxEncodedHeaderBytes)
+	}
+	if len(parts[2]) > maxEncodedSignatureBytes {
+		return nil, fmt.Errorf("JWS signature too large: %d bytes exceeds limit of %d bytes", len(parts[2]), maxEncodedSignatureBytes)
 	}
 
-	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	headerBytes, err := decodePart(parts[0], opts.AllowPadding)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode header: %w", err)
 	}
@@ -43,12 +116,12 @@ func Parse(compact string) (*ParsedJWS, error) {
 		return nil, fmt.Errorf("failed to parse header: %w", err)
 	}
 
-	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	payload, err := decodePart(parts[1], opts.AllowPadding)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode payload: %w", err)
 	}
 
-	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	signature, err := decodePart(parts[2], opts.AllowPadding)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode signature: %w", err)
 	}
@@ -65,12 +138,28 @@ func Parse(compact string) (*ParsedJWS, error) {
 	}, nil
 }
 
+// decodePart decodes one compact JWS part. Padding is reported as
+// ErrPaddingPresent unless allowPadding is set; any other failure is an
+// invalid-character or length error from the decoder.
+func decodePart(s string, allowPadding bool) ([]byte, error) {
+	if !strings.HasSuffix(s, "=") {
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	if !allowPadding {
+		return nil, ErrPaddingPresent
+	}
+	return base64.URLEncoding.DecodeString(s)
+}
+
 // ValidateHeader validates the JWS header at the low level.
 //
 // This function is typ-agnostic: it accepts both interaction-record+jwt (current)
 // and peac-receipt/0.1 (legacy). Format enforcement (requiring a specific typ)
 // belongs in the protocol layer (VerifyLocal), not in the generic JWS helper.
 func ValidateHeader(header Header) error {
+	if strings.EqualFold(header.Algorithm, "none") {
+		return ErrAlgNone
+	}
 	if header.Algorithm != "EdDSA" {
 		return fmt.Errorf("unsupported algorithm: %s (expected EdDSA)", header.Algorithm)
 	}
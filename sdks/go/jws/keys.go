@@ -0,0 +1,84 @@
+package jws
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/peacprotocol/peac/sdks/go/jwks"
+)
+
+// ParsePublicKeyPEM parses an Ed25519 public key from a PEM-encoded
+// SubjectPublicKeyInfo ("PUBLIC KEY") block.
+func ParsePublicKeyPEM(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("unexpected PEM block type %q: expected PUBLIC KEY", block.Type)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type %T: expected Ed25519", key)
+	}
+	return pub, nil
+}
+
+// ParsePublicKeyJWK parses an Ed25519 public key from a single JWK JSON
+// object (kty "OKP", crv "Ed25519"). Private key material is rejected.
+func ParsePublicKeyJWK(data []byte) (ed25519.PublicKey, error) {
+	var jwk struct {
+		KeyType string `json:"kty"`
+		Curve   string `json:"crv"`
+		X       string `json:"x"`
+		D       string `json:"d"`
+	}
+	if err := json.Unmarshal(data, &jwk); err != nil {
+		return nil, fmt.Errorf("failed to parse JWK: %w", err)
+	}
+	if jwk.KeyType != "OKP" {
+		return nil, fmt.Errorf("unsupported JWK kty %q: expected OKP", jwk.KeyType)
+	}
+	if jwk.Curve != "Ed25519" {
+		return nil, fmt.Errorf("unsupported JWK crv %q: expected Ed25519", jwk.Curve)
+	}
+	if jwk.D != "" {
+		return nil, fmt.Errorf("JWK contains private key material")
+	}
+	if jwk.X == "" {
+		return nil, fmt.Errorf("JWK missing x")
+	}
+	raw, err := Decode(jwk.X)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWK x: %w", err)
+	}
+	return ParsePublicKeyFromBytes(raw)
+}
+
+// JWK exports the public half of k as an OKP Ed25519 JWK for publishing in a
+// JWKS, with alg "EdDSA" and use "sig". status sets peac:status (e.g.
+// jwks.StatusDeprecated during rotation) and validFrom sets peac:valid_from
+// as RFC 3339 UTC; each is omitted when empty or zero.
+func (k *SigningKey) JWK(status string, validFrom time.Time) jwks.JWK {
+	jwk := jwks.JWK{
+		KeyType:   "OKP",
+		KeyID:     k.keyID,
+		Algorithm: "EdDSA",
+		Use:       "sig",
+		Curve:     "Ed25519",
+		X:         Encode(k.PublicKey()),
+		Status:    status,
+	}
+	if !validFrom.IsZero() {
+		jwk.ValidFrom = validFrom.UTC().Format(time.RFC3339)
+	}
+	return jwk
+}
@@ -0,0 +1,116 @@
+package jws
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/peacprotocol/peac/sdks/go/jwks"
+)
+
+func TestParsePublicKeyPEM(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(nil)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecDER, _ := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{"valid", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), false},
+		{"not PEM", []byte("not a key"), true},
+		{"wrong block type", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), true},
+		{"wrong curve", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: ecDER}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePublicKeyPEM(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePublicKeyPEM() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(got, pub) {
+				t.Error("parsed key does not match")
+			}
+		})
+	}
+}
+
+func TestParsePublicKeyJWK(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(nil)
+	x := Encode(pub)
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"valid", `{"kty":"OKP","crv":"Ed25519","kid":"k1","x":"` + x + `"}`, false},
+		{"invalid JSON", `{`, true},
+		{"wrong kty", `{"kty":"EC","crv":"P-256","x":"` + x + `"}`, true},
+		{"wrong crv", `{"kty":"OKP","crv":"X25519","x":"` + x + `"}`, true},
+		{"missing x", `{"kty":"OKP","crv":"Ed25519"}`, true},
+		{"wrong size", `{"kty":"OKP","crv":"Ed25519","x":"` + Encode(pub[:16]) + `"}`, true},
+		{"private key", `{"kty":"OKP","crv":"Ed25519","x":"` + x + `","d":"` + x + `"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePublicKeyJWK([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePublicKeyJWK() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(got, pub) {
+				t.Error("parsed key does not match")
+			}
+		})
+	}
+}
+
+func TestSigningKeyJWK(t *testing.T) {
+	key, err := GenerateSigningKey("rotating-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain, _ := json.Marshal(key.JWK("", time.Time{}))
+	if bytes.Contains(plain, []byte("peac:status")) || bytes.Contains(plain, []byte("peac:valid_from")) {
+		t.Errorf("zero status/validFrom should be omitted: %s", plain)
+	}
+
+	validFrom := time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("X", 3600))
+	jwk := key.JWK(jwks.StatusDeprecated, validFrom)
+	if jwk.ValidFrom != "2026-01-02T02:04:05Z" {
+		t.Errorf("ValidFrom = %q, want RFC 3339 UTC", jwk.ValidFrom)
+	}
+
+	// Round-trip through the public parsers and ToKeySet.
+	data, _ := json.Marshal(jwk)
+	pub, err := ParsePublicKeyJWK(data)
+	if err != nil || !pub.Equal(key.PublicKey()) {
+		t.Fatalf("ParsePublicKeyJWK() = %v, %v", pub, err)
+	}
+	var set jwks.JWKS
+	if err := json.Unmarshal([]byte(`{"keys":[`+string(data)+`]}`), &set); err != nil {
+		t.Fatal(err)
+	}
+	ks, err := set.ToKeySetStrict()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, ok := ks.Status("rotating-key"); !ok || status != jwks.StatusDeprecated {
+		t.Errorf("Status() = %q, %v, want deprecated", status, ok)
+	}
+}
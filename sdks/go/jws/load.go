@@ -0,0 +1,120 @@
+package jws
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadSigningKey decodes a signing key from source, detecting the encoding:
+//
+//   - PEM "PRIVATE KEY" block (PKCS #8)
+//   - hex
+//   - base64 (standard or URL alphabet, padded or not)
+//
+// A decoded 32-byte value is treated as a seed (NewSigningKeyFromSeed) and a
+// 64-byte value as a full private key (NewSigningKey), whose public half must
+// match its seed. Surrounding whitespace is ignored. Errors never include key
+// material.
+func LoadSigningKey(source, keyID string) (*SigningKey, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, fmt.Errorf("signing key source is empty")
+	}
+
+	if strings.HasPrefix(source, "-----BEGIN") {
+		return NewSigningKeyFromPEM([]byte(source), keyID)
+	}
+
+	raw, err := decodeKeyBytes(source)
+	if err != nil {
+		return nil, err
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return NewSigningKeyFromSeed(raw, keyID)
+	case ed25519.PrivateKeySize:
+		private := ed25519.PrivateKey(raw)
+		if !bytes.Equal(ed25519.NewKeyFromSeed(private.Seed()), private) {
+			return nil, fmt.Errorf("invalid private key: public key does not match seed")
+		}
+		return NewSigningKey(private, keyID)
+	default:
+		return nil, fmt.Errorf("invalid signing key size: expected %d-byte seed or %d-byte private key, got %d bytes",
+			ed25519.SeedSize, ed25519.PrivateKeySize, len(raw))
+	}
+}
+
+// LoadSigningKeyFromEnv loads a signing key from the named environment
+// variable. See LoadSigningKey for the accepted encodings.
+func LoadSigningKeyFromEnv(envVar, keyID string) (*SigningKey, error) {
+	source, ok := os.LookupEnv(envVar)
+	if !ok {
+		return nil, fmt.Errorf("environment variable %s is not set", envVar)
+	}
+	key, err := LoadSigningKey(source, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", envVar, err)
+	}
+	return key, nil
+}
+
+// LoadSigningKeyFromFile loads a signing key from a file. See LoadSigningKey
+// for the accepted encodings.
+func LoadSigningKeyFromFile(path, keyID string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	key, err := LoadSigningKey(string(data), keyID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// NewSigningKeyFromPEM creates a signing key from a PKCS #8 PEM "PRIVATE KEY"
+// block, as Ed25519 keys are usually stored in secret managers. Non-Ed25519
+// keys, other block types, and malformed PEM are rejected. Errors never
+// include key material.
+func NewSigningKeyFromPEM(pemBytes []byte, keyID string) (*SigningKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("invalid PEM signing key")
+	}
+	if block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("unexpected PEM block type %q: expected PRIVATE KEY", block.Type)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PKCS #8 private key")
+	}
+	private, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T: expected Ed25519", key)
+	}
+	return NewSigningKey(private, keyID)
+}
+
+// decodeKeyBytes decodes hex first, since a hex string is also valid base64
+// but never the other way round for key-sized input.
+func decodeKeyBytes(s string) ([]byte, error) {
+	if raw, err := hex.DecodeString(s); err == nil {
+		return raw, nil
+	}
+	for _, enc := range []*base64.Encoding{
+		base64.StdEncoding, base64.RawStdEncoding,
+		base64.URLEncoding, base64.RawURLEncoding,
+	} {
+		if raw, err := enc.DecodeString(s); err == nil {
+			return raw, nil
+		}
+	}
+	return nil, fmt.Errorf("signing key is not valid PEM, hex, or base64")
+}
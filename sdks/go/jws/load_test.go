@@ -0,0 +1,116 @@
+package jws
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSigningKey(t *testing.T) {
+	_, private, _ := ed25519.GenerateKey(nil)
+	seed := private.Seed()
+	der, _ := x509.MarshalPKCS8PrivateKey(private)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecDER, _ := x509.MarshalPKCS8PrivateKey(ecKey)
+
+	mismatched := make([]byte, ed25519.PrivateKeySize)
+	copy(mismatched, private)
+	mismatched[40] ^= 0xff
+
+	tests := []struct {
+		name    string
+		source  string
+		wantErr bool
+	}{
+		{"hex seed", hex.EncodeToString(seed), false},
+		{"hex private key", hex.EncodeToString(private), false},
+		{"base64 seed", base64.StdEncoding.EncodeToString(seed), false},
+		{"base64url private key", base64.RawURLEncoding.EncodeToString(private), false},
+		{"PEM with whitespace", "\n" + string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})) + "\n", false},
+		{"empty", "  ", true},
+		{"wrong size", hex.EncodeToString(seed[:16]), true},
+		{"mismatched public half", hex.EncodeToString(mismatched), true},
+		{"not encoded", "not a key!", true},
+		{"PEM wrong type", string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), true},
+		{"PEM wrong curve", string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecDER})), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := LoadSigningKey(tt.source, "kid-1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadSigningKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !bytes.Equal(key.PublicKey(), private.Public().(ed25519.PublicKey)) {
+				t.Error("loaded key does not match")
+			}
+			if key.KeyID() != "kid-1" {
+				t.Errorf("KeyID() = %s, want kid-1", key.KeyID())
+			}
+		})
+	}
+}
+
+func TestNewSigningKeyFromPEM(t *testing.T) {
+	_, private, _ := ed25519.GenerateKey(nil)
+	der, _ := x509.MarshalPKCS8PrivateKey(private)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecDER, _ := x509.MarshalPKCS8PrivateKey(ecKey)
+
+	key, err := NewSigningKeyFromPEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), "kid-1")
+	if err != nil {
+		t.Fatalf("NewSigningKeyFromPEM() error = %v", err)
+	}
+	if !bytes.Equal(key.PublicKey(), private.Public().(ed25519.PublicKey)) {
+		t.Error("loaded key does not match")
+	}
+
+	tests := []struct {
+		name string
+		pem  []byte
+	}{
+		{"not PEM", []byte("not a key")},
+		{"malformed PKCS #8", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte{0x30, 0x00}})},
+		{"non-Ed25519", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecDER})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSigningKeyFromPEM(tt.pem, "kid-1"); err == nil {
+				t.Error("NewSigningKeyFromPEM() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestLoadSigningKeyFromEnvAndFile(t *testing.T) {
+	_, private, _ := ed25519.GenerateKey(nil)
+	encoded := hex.EncodeToString(private.Seed())
+
+	t.Setenv("PEAC_TEST_SIGNING_KEY", encoded)
+	if _, err := LoadSigningKeyFromEnv("PEAC_TEST_SIGNING_KEY", "kid-1"); err != nil {
+		t.Errorf("LoadSigningKeyFromEnv() error = %v", err)
+	}
+	if _, err := LoadSigningKeyFromEnv("PEAC_TEST_SIGNING_KEY_UNSET", "kid-1"); err == nil {
+		t.Error("expected error for unset variable")
+	}
+
+	path := filepath.Join(t.TempDir(), "signing.key")
+	if err := os.WriteFile(path, []byte(encoded+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSigningKeyFromFile(path, "kid-1"); err != nil {
+		t.Errorf("LoadSigningKeyFromFile() error = %v", err)
+	}
+}
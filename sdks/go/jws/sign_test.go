@@ -3,7 +3,10 @@ package jws
 import (
 	"bytes"
 	"crypto/ed25519"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -386,6 +389,41 @@ func TestValidateHeader_UnsupportedAlgorithm(t *testing.T) {
 	}
 }
 
+func TestUnsecuredJWS_Rejected(t *testing.T) {
+	key, err := GenerateSigningKey("key-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := Encode([]byte(`{"iss":"https://example.com"}`))
+	tests := []struct {
+		name          string
+		alg           string
+		sig           string
+		wantHeaderErr error
+		wantErr       error
+	}{
+		{"alg none", "none", "", ErrAlgNone, ErrAlgNone},
+		{"alg None", "None", "", ErrAlgNone, ErrAlgNone},
+		{"alg none with signature", "none", Encode([]byte("sig")), ErrAlgNone, ErrAlgNone},
+		{"EdDSA empty signature", "EdDSA", "", nil, ErrEmptySignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := Encode([]byte(`{"alg":"` + tt.alg + `","typ":"interaction-record+jwt","kid":"key-001"}`))
+			parsed, err := Parse(header + "." + payload + "." + tt.sig)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if err := ValidateHeader(parsed.Header); !errors.Is(err, tt.wantHeaderErr) {
+				t.Errorf("ValidateHeader() error = %v, want %v", err, tt.wantHeaderErr)
+			}
+			if err := VerifyJWS(parsed, key.PublicKey()); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyJWS() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidateHeader_MissingKeyID(t *testing.T) {
 	header := Header{
 		Algorithm: "EdDSA",
@@ -450,3 +488,135 @@ func TestInteractionRecordTyp_Value(t *testing.T) {
 		t.Errorf("InteractionRecordTyp = %s, want interaction-record+jwt", InteractionRecordTyp)
 	}
 }
+
+// Flattened JSON serialization tests
+
+func TestSigningKey_SignJSON_RoundTrip(t *testing.T) {
+	key, _ := GenerateSigningKey("json-key")
+	payload := []byte(`{"iss":"https://example.com"}`)
+
+	out, err := key.SignJSON(payload)
+	if err != nil {
+		t.Fatalf("SignJSON() error = %v", err)
+	}
+
+	var members map[string]any
+	if err := json.Unmarshal(out, &members); err != nil {
+		t.Fatalf("SignJSON() output is not JSON: %v", err)
+	}
+	for _, name := range []string{"protected", "payload", "signature"} {
+		if _, ok := members[name]; !ok {
+			t.Errorf("SignJSON() output missing %q member", name)
+		}
+	}
+
+	parsed, err := ParseJSON(out)
+	if err != nil {
+		t.Fatalf("ParseJSON() error = %v", err)
+	}
+	if parsed.Header.Type != DefaultReceiptTyp {
+		t.Errorf("Type = %s, want %s", parsed.Header.Type, DefaultReceiptTyp)
+	}
+	if !bytes.Equal(parsed.Payload, payload) {
+		t.Errorf("Payload = %s, want %s", parsed.Payload, payload)
+	}
+	if err := VerifyJWS(parsed, key.PublicKey()); err != nil {
+		t.Errorf("VerifyJWS() error = %v", err)
+	}
+
+	compact, _ := key.Sign(payload)
+	if parsed.CompactSerialization != compact {
+		t.Error("ParseJSON() compact form does not match Sign() output")
+	}
+}
+
+func TestParseJSON_Rejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"not json", `eyJhbGciOiJFZERTQSJ9.e30.sig`},
+		{"missing protected", `{"payload":"e30","signature":"AA"}`},
+		{"missing signature", `{"protected":"e30","payload":"e30"}`},
+		{"unprotected header", `{"protected":"e30","header":{"kid":"k"},"payload":"e30","signature":"AA"}`},
+		{"general serialization", `{"payload":"e30","signatures":[]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseJSON([]byte(tt.input)); err == nil {
+				t.Error("ParseJSON() should reject input")
+			}
+		})
+	}
+}
+
+func TestParseWithLimit(t *testing.T) {
+	key, _ := GenerateSigningKey("key-1")
+	compact, err := key.Sign([]byte(`{"iss":"https://example.com"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParseWithLimit(compact, len(compact)); err != nil {
+		t.Errorf("ParseWithLimit() at exact limit error = %v", err)
+	}
+	if _, err := ParseWithLimit(compact, len(compact)-1); err == nil {
+		t.Error("expected error for input over limit")
+	}
+	if _, err := ParseWithLimit(compact, 0); err != nil {
+		t.Errorf("ParseWithLimit() with no limit error = %v", err)
+	}
+}
+
+func TestParseWithOptions_Padding(t *testing.T) {
+	key, _ := GenerateSigningKey("key-1")
+	compact, err := key.Sign([]byte(`{"iss":"https://example.com"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Re-encode every part as padded base64url, as a lax encoder would, and
+	// sign the padded input so the signature stays valid.
+	parts := strings.Split(compact, ".")
+	header, _ := Decode(parts[0])
+	payload, _ := Decode(parts[1])
+	pad := base64.URLEncoding.EncodeToString
+	signingInput := pad(header) + "." + pad(payload)
+	padded := signingInput + "." + pad(ed25519.Sign(key.privateKey, []byte(signingInput)))
+	if !strings.Contains(padded, "=") {
+		t.Fatal("test input has no padding")
+	}
+
+	tests := []struct {
+		name    string
+		compact string
+		opts    ParseOptions
+		wantErr error
+	}{
+		{"unpadded strict", compact, ParseOptions{}, nil},
+		{"unpadded tolerant", compact, ParseOptions{AllowPadding: true}, nil},
+		{"padded strict", padded, ParseOptions{}, ErrPaddingPresent},
+		{"padded tolerant", padded, ParseOptions{AllowPadding: true}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseWithOptions(tt.compact, tt.opts)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseWithOptions() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseWithOptions() error = %v", err)
+			}
+			if err := VerifyJWS(parsed, key.PublicKey()); err != nil {
+				t.Errorf("VerifyJWS() error = %v", err)
+			}
+		})
+	}
+
+	// Invalid characters are not reported as padding.
+	if _, err := ParseWithOptions("e30.e3*.sig", ParseOptions{AllowPadding: true}); err == nil || errors.Is(err, ErrPaddingPresent) {
+		t.Errorf("ParseWithOptions() error = %v, want a decode error", err)
+	}
+}
@@ -0,0 +1,25 @@
+package peac
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/peacprotocol/peac/sdks/go/jwks"
+	"github.com/peacprotocol/peac/sdks/go/jws"
+)
+
+// NewKeyMaterial generates an Ed25519 signing key with the given key ID and
+// returns it together with a single-key JWKS publishing its public half,
+// ready to serve at jwks.DefaultJWKSPath under the issuer. Sign records
+// with the key via IssueOptions.SigningKey; verifiers resolve it from the
+// served JWKS by kid.
+func NewKeyMaterial(keyID string) (*jws.SigningKey, *jwks.JWKS, error) {
+	if keyID == "" {
+		return nil, nil, fmt.Errorf("key ID is required")
+	}
+	key, err := jws.GenerateSigningKey(keyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, &jwks.JWKS{Keys: []jwks.JWK{key.JWK("", time.Time{})}}, nil
+}
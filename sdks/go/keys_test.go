@@ -0,0 +1,55 @@
+package peac_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	peac "github.com/peacprotocol/peac/sdks/go"
+	"github.com/peacprotocol/peac/sdks/go/jwks"
+)
+
+// Issue a record, serve the matching JWKS, and verify the record against it.
+func ExampleNewKeyMaterial() {
+	key, set, err := peac.NewKeyMaterial("quickstart-key-1")
+	if err != nil {
+		panic(err)
+	}
+
+	// Serve the JWKS where verifiers discover it.
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+jwks.DefaultJWKSPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(set)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	issued, err := peac.Issue(peac.IssueOptions{
+		Iss:        "https://publisher.example",
+		Kind:       peac.KindEvidence,
+		Type:       "org.peacprotocol/quickstart",
+		SigningKey: key,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// A verifier fetches the JWKS and resolves the key by kid.
+	fetched, err := jwks.Fetch(context.Background(), srv.URL+jwks.DefaultJWKSPath, jwks.DefaultFetchOptions())
+	if err != nil {
+		panic(err)
+	}
+	keySet, err := fetched.ToKeySet()
+	if err != nil {
+		panic(err)
+	}
+	result := peac.VerifyLocal(issued.JWS, peac.VerifyLocalOptions{
+		KeySet: keySet,
+		Issuer: "https://publisher.example",
+	})
+	fmt.Println(result.Valid, result.Kid)
+	// Output: true quickstart-key-1
+}
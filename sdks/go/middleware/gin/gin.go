@@ -20,9 +20,11 @@
 package gin
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	peac "github.com/peacprotocol/peac/sdks/go"
 	"github.com/peacprotocol/peac/sdks/go/jwks"
+	"github.com/peacprotocol/peac/sdks/go/jws"
 	"github.com/peacprotocol/peac/sdks/go/middleware"
 	"net/http"
 	"strings"
@@ -46,12 +48,27 @@ type Config struct {
 	// HeaderName is the header containing the receipt (default: "PEAC-Receipt").
 	HeaderName string
 
+	// UseAuthorizationHeader falls back to the Authorization header when
+	// HeaderName is absent or empty, for clients that send the receipt as a
+	// standard Bearer token. An Authorization header using the PEAC scheme,
+	// answering the WWW-Authenticate challenge, is accepted as the fallback
+	// even when this is false.
+	UseAuthorizationHeader bool
+
+	// MaxReceiptBytes caps the receipt length (default: jws.DefaultMaxJWSBytes).
+	// Negative disables the cap.
+	MaxReceiptBytes int
+
 	// Optional enables optional receipt verification.
 	Optional bool
 
 	// JWKSCache is an optional shared JWKS cache.
 	JWKSCache *jwks.Cache
 
+	// PreVerifyGate is called before the receipt is read and verified. A
+	// non-nil error short-circuits the request through ErrorHandler.
+	PreVerifyGate func(r *http.Request) error
+
 	// ErrorHandler is called when verification fails.
 	ErrorHandler func(c *gin.Context, err error)
 }
@@ -59,10 +76,11 @@ type Config struct {
 // DefaultConfig returns the default middleware configuration.
 func DefaultConfig() Config {
 	return Config{
-		MaxAge:     time.Hour,
-		ClockSkew:  30 * time.Second,
-		HeaderName: "PEAC-Receipt",
-		Optional:   false,
+		MaxAge:          time.Hour,
+		ClockSkew:       30 * time.Second,
+		HeaderName:      "PEAC-Receipt",
+		MaxReceiptBytes: jws.DefaultMaxJWSBytes,
+		Optional:        false,
 	}
 }
 
@@ -78,39 +96,49 @@ func Verifier(cfg Config) gin.HandlerFunc {
 	if cfg.ClockSkew == 0 {
 		cfg.ClockSkew = 30 * time.Second
 	}
+	if cfg.MaxReceiptBytes == 0 {
+		cfg.MaxReceiptBytes = jws.DefaultMaxJWSBytes
+	}
 	if cfg.ErrorHandler == nil {
 		cfg.ErrorHandler = defaultErrorHandler
 	}
 
 	return func(c *gin.Context) {
-		receipt := c.GetHeader(cfg.HeaderName)
-
-		// Handle missing receipt
-		if receipt == "" {
-			if cfg.Optional {
-				c.Next()
+		if cfg.PreVerifyGate != nil {
+			if err := cfg.PreVerifyGate(c.Request); err != nil {
+				cfg.ErrorHandler(c, err)
+				c.Abort()
 				return
 			}
-			err := peac.NewPEACError(peac.ErrIdentityMissing, "PEAC-Receipt header is required")
-			cfg.ErrorHandler(c, err)
-			c.Abort()
-			return
 		}
 
-		// Remove "Bearer " prefix if present
-		receipt = strings.TrimPrefix(receipt, "Bearer ")
-
-		// Verify the receipt
-		result, err := peac.Verify(receipt, peac.VerifyOptions{
-			Issuer:    cfg.Issuer,
-			Audience:  cfg.Audience,
-			MaxAge:    cfg.MaxAge,
-			ClockSkew: cfg.ClockSkew,
-			JWKSCache: cfg.JWKSCache,
-			Context:   c.Request.Context(),
-		})
+		headerName := cfg.HeaderName
+		if strings.TrimSpace(c.GetHeader(headerName)) == "" &&
+			(cfg.UseAuthorizationHeader || peac.HasPEACScheme(c.GetHeader("Authorization"))) {
+			headerName = "Authorization"
+		}
 
+		// Read, unwrap, size-check, and verify the receipt
+		receipt, err := peac.ReceiptFromRequest(c.Request, headerName)
+		var result *peac.VerifyResult
+		if err == nil {
+			result, err = peac.Verify(receipt, peac.VerifyOptions{
+				Issuer:          cfg.Issuer,
+				Audience:        cfg.Audience,
+				MaxAge:          cfg.MaxAge,
+				ClockSkew:       cfg.ClockSkew,
+				JWKSCache:       cfg.JWKSCache,
+				MaxReceiptBytes: cfg.MaxReceiptBytes,
+				Context:         c.Request.Context(),
+			})
+		}
 		if err != nil {
+			// Handle missing receipt
+			var peacErr *peac.PEACError
+			if cfg.Optional && errors.As(err, &peacErr) && peacErr.Code == peac.ErrIdentityMissing {
+				c.Next()
+				return
+			}
 			cfg.ErrorHandler(c, err)
 			c.Abort()
 			return
@@ -160,28 +188,7 @@ func GetResult(c *gin.Context) *peac.VerifyResult {
 	return result.(*peac.VerifyResult)
 }
 
-// defaultErrorHandler sends a JSON error response.
+// defaultErrorHandler sends an RFC 9457 problem response.
 func defaultErrorHandler(c *gin.Context, err error) {
-	status := http.StatusUnauthorized
-	code := "UNKNOWN_ERROR"
-	message := err.Error()
-
-	if peacErr, ok := err.(*peac.PEACError); ok {
-		status = peacErr.HTTPStatus()
-		code = string(peacErr.Code)
-		message = peacErr.Message
-	}
-
-	resp := gin.H{
-		"type":   "https://www.peacprotocol.org/errors/" + strings.ToLower(code),
-		"title":  code,
-		"status": status,
-		"detail": message,
-	}
-
-	if peacErr, ok := err.(*peac.PEACError); ok && len(peacErr.Details) > 0 {
-		resp["peac_error"] = peacErr.Details
-	}
-
-	c.JSON(status, resp)
+	_ = middleware.ErrorProblem(c.Request, err).WriteResponse(c.Writer)
 }
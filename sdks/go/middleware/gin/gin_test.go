@@ -1,6 +1,7 @@
 package gin_test
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -143,3 +144,54 @@ func TestCaseInsensitivePeacReceiptHeader(t *testing.T) {
 		t.Fatalf("downstream reached despite 400")
 	}
 }
+
+// TestAuthorizationHeaderFallback checks that UseAuthorizationHeader reads
+// a Bearer token from Authorization when PEAC-Receipt is absent.
+func TestAuthorizationHeaderFallback(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		fallback bool
+		want     int
+	}{
+		{"disabled", false, http.StatusUnauthorized},
+		{"enabled", true, http.StatusBadRequest},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultCfg()
+			cfg.UseAuthorizationHeader = tt.fallback
+			e := newEngine(peacgin.Verifier(cfg), nil)
+
+			req := httptest.NewRequest("GET", "/protected", nil)
+			req.Header.Set("Authorization", "Bearer not-a-jws")
+			rr := httptest.NewRecorder()
+			e.ServeHTTP(rr, req)
+
+			if rr.Code != tt.want {
+				t.Fatalf("want %d, got %d; body=%s", tt.want, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+// TestPreVerifyGateShortCircuits asserts a gate error stops the request
+// before the receipt is examined.
+func TestPreVerifyGateShortCircuits(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.Optional = true
+	cfg.PreVerifyGate = func(r *http.Request) error {
+		return errors.New("blocked")
+	}
+	hit := false
+	e := newEngine(peacgin.Verifier(cfg), &hit)
+
+	req := httptest.NewRequest("GET", "/protected", nil)
+	rr := httptest.NewRecorder()
+	e.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("want 401, got %d; body=%s", rr.Code, rr.Body.String())
+	}
+	if hit {
+		t.Fatalf("downstream reached despite gate error")
+	}
+}
@@ -3,13 +3,14 @@ package middleware
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
 
 	peac "github.com/peacprotocol/peac/sdks/go"
 	"github.com/peacprotocol/peac/sdks/go/jwks"
+	"github.com/peacprotocol/peac/sdks/go/jws"
 )
 
 // ContextKey is the type for context keys.
@@ -40,6 +41,18 @@ type Config struct {
 	// HeaderName is the header containing the receipt (default: "PEAC-Receipt").
 	HeaderName string
 
+	// UseAuthorizationHeader falls back to the Authorization header when
+	// HeaderName is absent or empty, for clients that send the receipt as a
+	// standard Bearer token. An Authorization header using the PEAC scheme,
+	// answering the WWW-Authenticate challenge, is accepted as the fallback
+	// even when this is false.
+	UseAuthorizationHeader bool
+
+	// MaxReceiptBytes caps the receipt length; larger receipts are rejected
+	// before decoding (default: jws.DefaultMaxJWSBytes). Negative disables
+	// the cap.
+	MaxReceiptBytes int
+
 	// Optional enables optional receipt verification.
 	// If true, requests without receipts are allowed through.
 	// If false (default), requests without receipts return 401.
@@ -48,6 +61,12 @@ type Config struct {
 	// JWKSCache is an optional shared JWKS cache.
 	JWKSCache *jwks.Cache
 
+	// PreVerifyGate is called before the receipt is read and verified, so
+	// operators can attach their own throttle or allowlist. A non-nil error
+	// short-circuits the request through ErrorHandler; return a *PEACError
+	// to control the response status. Nil (default) disables the gate.
+	PreVerifyGate func(r *http.Request) error
+
 	// ErrorHandler is called when verification fails.
 	// If nil, a default JSON error response is sent.
 	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
@@ -99,12 +118,13 @@ type Config struct {
 // defaults: panic recovery on, 1 MiB body cap, TrustProxyHeaders off.
 func DefaultConfig() Config {
 	return Config{
-		MaxAge:        time.Hour,
-		ClockSkew:     30 * time.Second,
-		HeaderName:    "PEAC-Receipt",
-		Optional:      false,
-		RecoverPanics: true,
-		MaxBodyBytes:  1 << 20, // 1 MiB
+		MaxAge:          time.Hour,
+		ClockSkew:       30 * time.Second,
+		HeaderName:      "PEAC-Receipt",
+		MaxReceiptBytes: jws.DefaultMaxJWSBytes,
+		Optional:        false,
+		RecoverPanics:   true,
+		MaxBodyBytes:    1 << 20, // 1 MiB
 	}
 }
 
@@ -120,6 +140,9 @@ func Middleware(cfg Config) func(http.Handler) http.Handler {
 	if cfg.ClockSkew == 0 {
 		cfg.ClockSkew = 30 * time.Second
 	}
+	if cfg.MaxReceiptBytes == 0 {
+		cfg.MaxReceiptBytes = jws.DefaultMaxJWSBytes
+	}
 	if cfg.ErrorHandler == nil {
 		cfg.ErrorHandler = defaultErrorHandler
 	}
@@ -169,33 +192,39 @@ func Middleware(cfg Config) func(http.Handler) http.Handler {
 				}
 			}
 
-			receipt := r.Header.Get(cfg.HeaderName)
-
-			// Handle missing receipt
-			if receipt == "" {
-				if cfg.Optional {
-					wrapped.ServeHTTP(w, r)
+			if cfg.PreVerifyGate != nil {
+				if err := cfg.PreVerifyGate(r); err != nil {
+					cfg.ErrorHandler(w, r, err)
 					return
 				}
-				err := peac.NewPEACError(peac.ErrIdentityMissing, "PEAC-Receipt header is required")
-				cfg.ErrorHandler(w, r, err)
-				return
 			}
 
-			// Remove "Bearer " prefix if present
-			receipt = strings.TrimPrefix(receipt, "Bearer ")
-
-			// Verify the receipt
-			result, err := peac.Verify(receipt, peac.VerifyOptions{
-				Issuer:    cfg.Issuer,
-				Audience:  cfg.Audience,
-				MaxAge:    cfg.MaxAge,
-				ClockSkew: cfg.ClockSkew,
-				JWKSCache: cfg.JWKSCache,
-				Context:   r.Context(),
-			})
+			headerName := cfg.HeaderName
+			if strings.TrimSpace(r.Header.Get(headerName)) == "" &&
+				(cfg.UseAuthorizationHeader || peac.HasPEACScheme(r.Header.Get("Authorization"))) {
+				headerName = "Authorization"
+			}
 
+			// Read, unwrap, size-check, and verify the receipt
+			receipt, err := peac.ReceiptFromRequest(r, headerName)
+			var result *peac.VerifyResult
+			if err == nil {
+				result, err = peac.Verify(receipt, peac.VerifyOptions{
+					Issuer:          cfg.Issuer,
+					Audience:        cfg.Audience,
+					MaxAge:          cfg.MaxAge,
+					ClockSkew:       cfg.ClockSkew,
+					JWKSCache:       cfg.JWKSCache,
+					MaxReceiptBytes: cfg.MaxReceiptBytes,
+					Context:         r.Context(),
+				})
+			}
 			if err != nil {
+				// Handle missing receipt
+				if cfg.Optional && isMissingReceipt(err) {
+					wrapped.ServeHTTP(w, r)
+					return
+				}
 				cfg.ErrorHandler(w, r, err)
 				return
 			}
@@ -232,33 +261,16 @@ func GetResult(r *http.Request) *peac.VerifyResult {
 	return result
 }
 
-// defaultErrorHandler sends a JSON error response.
-func defaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
-	status := http.StatusUnauthorized
-	code := "UNKNOWN_ERROR"
-	message := err.Error()
-
-	if peacErr, ok := err.(*peac.PEACError); ok {
-		status = peacErr.HTTPStatus()
-		code = string(peacErr.Code)
-		message = peacErr.Message
-	}
-
-	w.Header().Set("Content-Type", "application/problem+json")
-	w.WriteHeader(status)
-
-	resp := map[string]interface{}{
-		"type":   "https://www.peacprotocol.org/errors/" + strings.ToLower(code),
-		"title":  code,
-		"status": status,
-		"detail": message,
-	}
-
-	if peacErr, ok := err.(*peac.PEACError); ok && len(peacErr.Details) > 0 {
-		resp["peac_error"] = peacErr.Details
-	}
+// isMissingReceipt reports whether err is ReceiptFromRequest's missing-header
+// failure.
+func isMissingReceipt(err error) bool {
+	var peacErr *peac.PEACError
+	return errors.As(err, &peacErr) && peacErr.Code == peac.ErrIdentityMissing
+}
 
-	_ = json.NewEncoder(w).Encode(resp) // Error intentionally ignored in error handler
+// defaultErrorHandler sends an RFC 9457 problem response.
+func defaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	_ = ErrorProblem(r, err).WriteResponse(w) // Error intentionally ignored in error handler
 }
 
 // RequireReceipt creates a middleware that requires a valid PEAC receipt.
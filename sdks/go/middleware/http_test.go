@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	peac "github.com/peacprotocol/peac/sdks/go"
@@ -260,3 +261,187 @@ func TestCustomHeaderName(t *testing.T) {
 		t.Errorf("Status = %d, want %d", rec.Code, http.StatusUnauthorized)
 	}
 }
+
+func TestMiddlewareOversizedReceipt(t *testing.T) {
+	var got error
+	middleware := Middleware(Config{
+		Issuer:          "https://publisher.example",
+		MaxReceiptBytes: 128,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			got = err
+			w.WriteHeader(http.StatusBadRequest)
+		},
+	})
+
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Handler should not be called with oversized receipt")
+	}))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("PEAC-Receipt", strings.Repeat("a", 64)+"."+strings.Repeat("b", 64)+".c")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	peacErr, ok := got.(*peac.PEACError)
+	if !ok || peacErr.Code != peac.ErrInvalidFormat || !strings.Contains(peacErr.Message, "too large") {
+		t.Errorf("err = %v, want E_INVALID_FORMAT too large", got)
+	}
+}
+
+func TestMiddlewareAuthorizationHeaderFallback(t *testing.T) {
+	run := func(useAuthorization bool) error {
+		var got error
+		middleware := Middleware(Config{
+			Issuer:                 "https://publisher.example",
+			UseAuthorizationHeader: useAuthorization,
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				got = err
+				w.WriteHeader(http.StatusBadRequest)
+			},
+		})
+		handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("Authorization", "  bearer   not-a-jws ")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return got
+	}
+
+	err := run(false)
+	if peacErr, ok := err.(*peac.PEACError); !ok || peacErr.Code != peac.ErrIdentityMissing {
+		t.Errorf("without fallback: err = %v, want E_IDENTITY_MISSING", err)
+	}
+	// With the fallback the token is extracted and reaches JWS parsing.
+	err = run(true)
+	if peacErr, ok := err.(*peac.PEACError); !ok || peacErr.Code != peac.ErrInvalidFormat {
+		t.Errorf("with fallback: err = %v, want E_INVALID_FORMAT", err)
+	}
+}
+
+func TestMiddlewarePEACAuthorizationScheme(t *testing.T) {
+	run := func(headers map[string]string) error {
+		var got error
+		middleware := Middleware(Config{
+			Issuer: "https://publisher.example",
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				got = err
+				w.WriteHeader(http.StatusBadRequest)
+			},
+		})
+		handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		req := httptest.NewRequest("GET", "/test", nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return got
+	}
+
+	// The PEAC scheme is read from Authorization without opting in.
+	err := run(map[string]string{"Authorization": "peac not-a-jws"})
+	if peacErr, ok := err.(*peac.PEACError); !ok || peacErr.Code != peac.ErrInvalidFormat {
+		t.Errorf("PEAC scheme: err = %v, want E_INVALID_FORMAT", err)
+	}
+	// Bearer still requires UseAuthorizationHeader.
+	err = run(map[string]string{"Authorization": "Bearer not-a-jws"})
+	if peacErr, ok := err.(*peac.PEACError); !ok || peacErr.Code != peac.ErrIdentityMissing {
+		t.Errorf("Bearer scheme: err = %v, want E_IDENTITY_MISSING", err)
+	}
+	// PEAC-Receipt stays primary when both are present.
+	err = run(map[string]string{"PEAC-Receipt": "not-a-jws", "Authorization": "PEAC "})
+	if peacErr, ok := err.(*peac.PEACError); !ok || strings.Contains(peacErr.Message, "token is empty") {
+		t.Errorf("both headers: err = %v, want PEAC-Receipt to be read", err)
+	}
+}
+
+func TestMiddlewareEmptyBearerToken(t *testing.T) {
+	var got error
+	middleware := Middleware(Config{
+		Issuer: "https://publisher.example",
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			got = err
+			w.WriteHeader(http.StatusBadRequest)
+		},
+	})
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Handler should not be called with an empty token")
+	}))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("PEAC-Receipt", "Bearer ")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if peacErr, ok := got.(*peac.PEACError); !ok || peacErr.Code != peac.ErrInvalidFormat {
+		t.Errorf("err = %v, want E_INVALID_FORMAT", got)
+	}
+}
+
+func TestDefaultErrorHandlerInstance(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		prefix    string
+	}{
+		{"request id header", "req-123", "req-123"},
+		{"uri request id", "urn:uuid:0190c2d8-1b2a-7000-8000-000000000001", "urn:uuid:0190c2d8"},
+		{"generated", "", "urn:uuid:"},
+		{"request id with space", "req 123", "urn:uuid:"},
+		{"request id with quote", `req"123`, "urn:uuid:"},
+		{"bad percent escape", "req%zz", "urn:uuid:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/test", nil)
+			if tt.requestID != "" {
+				req.Header.Set(RequestIDHeader, tt.requestID)
+			}
+
+			defaultErrorHandler(rec, req, peac.NewPEACError(peac.ErrInvalidSignature, "test"))
+
+			var resp map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			instance, _ := resp["instance"].(string)
+			if !strings.HasPrefix(instance, tt.prefix) {
+				t.Errorf("instance = %q, want prefix %q", instance, tt.prefix)
+			}
+		})
+	}
+}
+
+func TestPreVerifyGate(t *testing.T) {
+	tests := []struct {
+		name       string
+		gateErr    error
+		wantStatus int
+	}{
+		{"blocked", peac.NewPEACError(peac.ErrInvalidFormat, "blocked"), http.StatusBadRequest},
+		{"allowed", nil, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateCalled := false
+			handler := Middleware(Config{
+				Issuer:   "https://publisher.example",
+				Audience: "https://agent.example",
+				Optional: true,
+				PreVerifyGate: func(r *http.Request) error {
+					gateCalled = true
+					return tt.gateErr
+				},
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest("GET", "/test", nil))
+
+			if !gateCalled {
+				t.Error("PreVerifyGate was not called")
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -152,6 +152,9 @@ func TestAdapterParity(t *testing.T) {
 			for _, a := range adapters() {
 				handler := a.verifier(sc.cfg)(downstream())
 				req := httptest.NewRequest(sc.method, sc.path, strings.NewReader(sc.body))
+				// Pin the problem instance; it is otherwise a fresh
+				// urn:uuid per response.
+				req.Header.Set(coremw.RequestIDHeader, "parity-request-1")
 				for k, v := range sc.headers {
 					req.Header.Set(k, v)
 				}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	peac "github.com/peacprotocol/peac/sdks/go"
+)
+
+// RequestIDHeader is the request header whose value, when present, is used
+// as the problem instance so error responses correlate with upstream logs.
+const RequestIDHeader = "X-Request-Id"
+
+// ProblemInstance returns an RFC 9457 instance URI identifying this error
+// occurrence: the request's X-Request-Id when it is a valid URI reference,
+// otherwise a fresh urn:uuid. Adapters share it so every error response is
+// correlatable.
+func ProblemInstance(r *http.Request) string {
+	if id := r.Header.Get(RequestIDHeader); isURIReference(id) {
+		return id
+	}
+	id, err := peac.DefaultIDGenerator().NewReceiptID()
+	if err != nil {
+		return ""
+	}
+	return "urn:uuid:" + id
+}
+
+// isURIReference reports whether s is a non-empty RFC 3986 URI reference:
+// only unreserved, reserved, and well-formed percent-encoded characters, in
+// a shape url.Parse accepts.
+func isURIReference(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '%':
+			if i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("-._~:/?#[]@!$&'()*+,;=", rune(c)) {
+				return false
+			}
+		}
+	}
+	_, err := url.Parse(s)
+	return err == nil
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// ErrorProblem builds the problem response for a verification error. Errors
+// that are not *peac.PEACError map to 401 UNKNOWN_ERROR.
+func ErrorProblem(r *http.Request, err error) *peac.Problem {
+	var p *peac.Problem
+	if peacErr, ok := err.(*peac.PEACError); ok {
+		p = peac.ProblemFromError(peacErr)
+	} else {
+		p = peac.NewProblem(http.StatusUnauthorized, "UNKNOWN_ERROR", err.Error())
+	}
+	p.Instance = ProblemInstance(r)
+	return p
+}
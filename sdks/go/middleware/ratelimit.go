@@ -19,13 +19,14 @@
 package middleware
 
 import (
-	"encoding/json"
 	"net"
 	"net/http"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	peac "github.com/peacprotocol/peac/sdks/go"
 )
 
 // RateLimitStrategy selects what the limiter keys its buckets on.
@@ -234,14 +235,8 @@ func writeRateLimitResponse(w http.ResponseWriter, retryAfter time.Duration) {
 		seconds = 1
 	}
 	w.Header().Set("Retry-After", strconv.Itoa(seconds))
-	w.Header().Set("Content-Type", "application/problem+json")
-	w.WriteHeader(http.StatusTooManyRequests)
-	resp := map[string]any{
-		"type":     "https://www.peacprotocol.org/errors/rate_limited",
-		"title":    "E_RATE_LIMITED",
-		"status":   http.StatusTooManyRequests,
-		"detail":   "rate limit exceeded",
-		"instance": "peac:middleware:rate-limit-exceeded",
-	}
-	_ = json.NewEncoder(w).Encode(resp)
+	p := peac.NewProblem(http.StatusTooManyRequests, "E_RATE_LIMITED", "rate limit exceeded")
+	p.Type = "https://www.peacprotocol.org/errors/rate_limited"
+	p.Instance = "peac:middleware:rate-limit-exceeded"
+	_ = p.WriteResponse(w)
 }
@@ -12,11 +12,12 @@
 package middleware
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"runtime/debug"
+
+	peac "github.com/peacprotocol/peac/sdks/go"
 )
 
 // recoverError wraps a recovered panic value for logging and reporting. The
@@ -76,16 +77,9 @@ func wrapWithRecover(next http.Handler, cfg Config) http.Handler {
 // the stack is not emitted over the wire (only logged via Logger) to avoid
 // leaking internal details.
 func writePanicResponse(w http.ResponseWriter, err *recoverError) {
-	w.Header().Set("Content-Type", "application/problem+json")
-	w.WriteHeader(http.StatusInternalServerError)
-	resp := map[string]any{
-		"type":     "https://www.peacprotocol.org/errors/peac_middleware_panic",
-		"title":    "PEAC_MIDDLEWARE_PANIC",
-		"status":   http.StatusInternalServerError,
-		"detail":   err.Error(),
-		"instance": "peac:middleware:panic-recovered",
-	}
-	_ = json.NewEncoder(w).Encode(resp) // best-effort write, ignore encoder err
+	p := peac.NewProblem(http.StatusInternalServerError, "PEAC_MIDDLEWARE_PANIC", err.Error())
+	p.Instance = "peac:middleware:panic-recovered"
+	_ = p.WriteResponse(w) // best-effort write, ignore encoder err
 }
 
 // isRecoverError reports whether the supplied error is a recoverError.
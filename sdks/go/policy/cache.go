@@ -0,0 +1,103 @@
+package policy
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+)
+
+// DefaultCacheEntries is the CachedEvaluator capacity used when maxEntries
+// is not positive.
+const DefaultCacheEntries = 1024
+
+// CachedEvaluator memoizes Evaluate results by a hash of the evaluation
+// context, evicting the least recently used entry when full. Evaluation is
+// pure given (policy, context), so cached results are exact as long as the
+// policy document is not mutated in place; swap it with SetPolicy instead.
+// It is safe for concurrent use.
+type CachedEvaluator struct {
+	mu         sync.Mutex
+	policy     *PolicyDocument
+	maxEntries int
+	order      *list.List // front is most recently used
+	entries    map[[sha256.Size]byte]*list.Element
+}
+
+type cacheEntry struct {
+	key    [sha256.Size]byte
+	result EvaluationResult
+}
+
+// NewCachedEvaluator creates a cached evaluator for policy holding at most
+// maxEntries results (DefaultCacheEntries if not positive).
+func NewCachedEvaluator(policy *PolicyDocument, maxEntries int) *CachedEvaluator {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheEntries
+	}
+	return &CachedEvaluator{
+		policy:     policy,
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[[sha256.Size]byte]*list.Element),
+	}
+}
+
+// Evaluate returns Evaluate(policy, context), from the cache when the same
+// context was seen before. Each call returns a fresh result; Constraints and
+// ReceiptRequirements are shared with the policy and must not be modified.
+func (e *CachedEvaluator) Evaluate(context *EvaluationContext) *EvaluationResult {
+	if context == nil {
+		context = &EvaluationContext{}
+	}
+	data, err := json.Marshal(context)
+	if err != nil {
+		// Not reachable for EvaluationContext; fall back to uncached.
+		return Evaluate(e.Policy(), context)
+	}
+	key := sha256.Sum256(data)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if el, ok := e.entries[key]; ok {
+		e.order.MoveToFront(el)
+		result := el.Value.(*cacheEntry).result
+		return &result
+	}
+
+	result := Evaluate(e.policy, context)
+	e.entries[key] = e.order.PushFront(&cacheEntry{key: key, result: *result})
+	if e.order.Len() > e.maxEntries {
+		oldest := e.order.Back()
+		e.order.Remove(oldest)
+		delete(e.entries, oldest.Value.(*cacheEntry).key)
+	}
+	return result
+}
+
+// Policy returns the policy being evaluated.
+func (e *CachedEvaluator) Policy() *PolicyDocument {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.policy
+}
+
+// SetPolicy replaces the policy being evaluated. The cache is cleared if the
+// policy pointer changes.
+func (e *CachedEvaluator) SetPolicy(policy *PolicyDocument) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if policy == e.policy {
+		return
+	}
+	e.policy = policy
+	e.order.Init()
+	clear(e.entries)
+}
+
+// Len returns the number of cached results.
+func (e *CachedEvaluator) Len() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.order.Len()
+}
@@ -0,0 +1,88 @@
+package policy
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCachedEvaluator(t *testing.T) {
+	policy := testPolicy()
+	e := NewCachedEvaluator(policy, 2)
+
+	ctx := &EvaluationContext{
+		Subject:       &Subject{Type: Human, Labels: []string{"subscribed"}},
+		Purpose:       PurposeCrawl,
+		LicensingMode: LicensingSubscription,
+	}
+	want := Evaluate(policy, ctx)
+	for i := 0; i < 2; i++ {
+		got := e.Evaluate(ctx)
+		if got.Decision != want.Decision || got.MatchedRule != want.MatchedRule {
+			t.Errorf("call %d = %s/%s, want %s/%s", i, got.Decision, got.MatchedRule, want.Decision, want.MatchedRule)
+		}
+	}
+	if e.Len() != 1 {
+		t.Errorf("Len = %d, want 1", e.Len())
+	}
+
+	// Mutating a returned result does not poison the cache.
+	e.Evaluate(ctx).Decision = Deny
+	if got := e.Evaluate(ctx).Decision; got != want.Decision {
+		t.Errorf("after mutation = %s, want %s", got, want.Decision)
+	}
+
+	// LRU eviction keeps the most recently used entries.
+	e.Evaluate(&EvaluationContext{Purpose: PurposeTrain})
+	e.Evaluate(ctx)
+	e.Evaluate(&EvaluationContext{Purpose: PurposeIndex})
+	if e.Len() != 2 {
+		t.Errorf("Len = %d, want 2", e.Len())
+	}
+
+	// Swapping the policy invalidates; re-setting the same pointer does not.
+	e.SetPolicy(policy)
+	if e.Len() != 2 {
+		t.Errorf("Len after same SetPolicy = %d, want 2", e.Len())
+	}
+	e.SetPolicy(nil)
+	if e.Len() != 0 {
+		t.Errorf("Len after SetPolicy = %d, want 0", e.Len())
+	}
+	if got := e.Evaluate(ctx); got.Reason != ReasonNilPolicy {
+		t.Errorf("nil policy reason = %q, want %q", got.Reason, ReasonNilPolicy)
+	}
+}
+
+// largePolicy returns a policy with n rules where only the last matches
+// agents, so uncached evaluation walks the whole list.
+func largePolicy(n int) *PolicyDocument {
+	rules := make([]PolicyRule, n)
+	for i := range rules {
+		rules[i] = PolicyRule{
+			Name:     fmt.Sprintf("rule-%d", i),
+			Subject:  &SubjectMatcher{ID: fmt.Sprintf("tenant-%d:*", i)},
+			Purpose:  Purposes{PurposeCrawl},
+			Decision: Deny,
+			Priority: i % 3,
+		}
+	}
+	rules[n-1].Subject = &SubjectMatcher{Type: Agent}
+	rules[n-1].Decision = Allow
+	return &PolicyDocument{Version: PolicyVersion, Rules: rules}
+}
+
+func BenchmarkEvaluate_100Rules(b *testing.B) {
+	policy := largePolicy(100)
+	ctx := &EvaluationContext{Subject: &Subject{Type: Agent, ID: "bot"}, Purpose: PurposeCrawl}
+	for b.Loop() {
+		Evaluate(policy, ctx)
+	}
+}
+
+func BenchmarkCachedEvaluator_100Rules(b *testing.B) {
+	e := NewCachedEvaluator(largePolicy(100), 0)
+	ctx := &EvaluationContext{Subject: &Subject{Type: Agent, ID: "bot"}, Purpose: PurposeCrawl}
+	for b.Loop() {
+		e.Evaluate(ctx)
+	}
+}
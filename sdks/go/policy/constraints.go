@@ -0,0 +1,84 @@
+package policy
+
+import "fmt"
+
+// Constraints carries rate limit and budget limits declared by a rule.
+//
+// Constraints are advisory: enforcement happens at the edge or application
+// layer. They mirror the TypeScript PolicyConstraints shape so a matched
+// rule's limits can be propagated into a 402 challenge or an issued receipt.
+type Constraints struct {
+	// RateLimit bounds requests per time window.
+	RateLimit *RateLimit `json:"rate_limit,omitempty"`
+
+	// Budget bounds total consumption.
+	Budget *Budget `json:"budget,omitempty"`
+}
+
+// RateLimit limits requests within a fixed window.
+type RateLimit struct {
+	// WindowSeconds is the window size in seconds.
+	WindowSeconds int `json:"window_s"`
+
+	// Max is the maximum number of requests allowed in the window.
+	Max int `json:"max"`
+
+	// RetryAfterSeconds is the Retry-After value to send when the limit is hit.
+	RetryAfterSeconds int `json:"retry_after_s,omitempty"`
+}
+
+// Budget limits total consumption.
+type Budget struct {
+	// MaxTokens is the maximum number of tokens allowed.
+	MaxTokens int `json:"max_tokens,omitempty"`
+
+	// MaxRequests is the maximum number of requests allowed.
+	MaxRequests int `json:"max_requests,omitempty"`
+}
+
+// validateConstraints validates a rule's constraints. All present limits
+// must be positive. It reports whether validation should continue.
+func validateConstraints(c *Constraints, field string, r *validationReport) bool {
+	if c == nil {
+		return true
+	}
+	if rl := c.RateLimit; rl != nil {
+		if !r.add(requirePositive(rl.WindowSeconds, field+".rate_limit.window_s")) {
+			return false
+		}
+		if !r.add(requirePositive(rl.Max, field+".rate_limit.max")) {
+			return false
+		}
+		if rl.RetryAfterSeconds < 0 && !r.add(negativeLimitError(field+".rate_limit.retry_after_s")) {
+			return false
+		}
+	}
+	if b := c.Budget; b != nil {
+		if b.MaxTokens < 0 && !r.add(negativeLimitError(field+".budget.max_tokens")) {
+			return false
+		}
+		if b.MaxRequests < 0 && !r.add(negativeLimitError(field+".budget.max_requests")) {
+			return false
+		}
+	}
+	return true
+}
+
+func requirePositive(v int, field string) error {
+	if v <= 0 {
+		return &ValidationError{
+			Code:    ErrCodeInvalidPolicy,
+			Message: fmt.Sprintf("%s must be a positive integer", field),
+			Field:   field,
+		}
+	}
+	return nil
+}
+
+func negativeLimitError(field string) error {
+	return &ValidationError{
+		Code:    ErrCodeInvalidPolicy,
+		Message: fmt.Sprintf("%s must not be negative", field),
+		Field:   field,
+	}
+}
@@ -0,0 +1,150 @@
+package policy
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ToDOT renders policy as a Graphviz DOT digraph of its evaluation flow:
+// rules in evaluation order (descending Priority, then array order), each
+// labeled with its match conditions, a "match" edge to its decision and a
+// "no match" edge to the next rule, ending at the default fallthrough. A
+// rule that can never be reached sits behind an earlier unconditional one,
+// which makes shadowing visible at a glance.
+//
+// A nil policy renders as a lone default deny, as Evaluate treats it.
+func ToDOT(policy *PolicyDocument) string {
+	var b strings.Builder
+	name := "policy"
+	var rules []PolicyRule
+	defaultDecision, defaultReason := Deny, ""
+	if policy != nil {
+		if policy.Name != "" {
+			name = policy.Name
+		}
+		rules = orderedRules(policy.Rules)
+		if policy.Defaults != nil {
+			defaultDecision, defaultReason = policy.Defaults.Decision, policy.Defaults.Reason
+		}
+	} else {
+		defaultReason = ReasonNilPolicy
+	}
+
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(name))
+	b.WriteString("\trankdir=TB;\n")
+	b.WriteString("\tnode [shape=box];\n")
+	b.WriteString("\tstart [shape=circle, label=\"request\"];\n")
+
+	var decisions []Decision
+	prev := "start"
+	edge := ""
+	for i, rule := range rules {
+		id := fmt.Sprintf("rule%d", i)
+		label := fmt.Sprintf("%d. %s\n%s", i+1, rule.Name, strings.Join(ruleConditions(&rule), "\n"))
+		fmt.Fprintf(&b, "\t%s [label=%s];\n", id, dotQuote(label))
+		fmt.Fprintf(&b, "\t%s -> %s%s;\n", prev, id, edge)
+		fmt.Fprintf(&b, "\t%s -> %s [label=\"match\"];\n", id, decisionNode(rule.Decision))
+		if !slices.Contains(decisions, rule.Decision) {
+			decisions = append(decisions, rule.Decision)
+		}
+		prev, edge = id, " [label=\"no match\"]"
+	}
+
+	label := "default"
+	if defaultReason != "" {
+		label += "\n" + defaultReason
+	}
+	fmt.Fprintf(&b, "\tdefault [shape=diamond, label=%s];\n", dotQuote(label))
+	fmt.Fprintf(&b, "\t%s -> default%s;\n", prev, edge)
+	fmt.Fprintf(&b, "\tdefault -> %s;\n", decisionNode(defaultDecision))
+	if !slices.Contains(decisions, defaultDecision) {
+		decisions = append(decisions, defaultDecision)
+	}
+
+	for _, d := range decisions {
+		fmt.Fprintf(&b, "\t%s [shape=ellipse, style=filled, fillcolor=%s, label=%s];\n",
+			decisionNode(d), decisionColor(d), dotQuote(string(d)))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// ruleConditions describes a rule's match conditions, one per line, or
+// "any request" for an unconditional rule.
+func ruleConditions(rule *PolicyRule) []string {
+	var conds []string
+	if s := rule.Subject; s != nil {
+		if s.Type != "" {
+			conds = append(conds, "subject.type = "+string(s.Type))
+		}
+		if len(s.Labels) > 0 {
+			conds = append(conds, "subject.labels include "+strings.Join(s.Labels, ", "))
+		}
+		if ids := subjectIDPatterns(s); len(ids) > 0 {
+			conds = append(conds, "subject.id ~ "+strings.Join(ids, " | "))
+		}
+		for _, k := range slices.Sorted(maps.Keys(s.Metadata)) {
+			conds = append(conds, fmt.Sprintf("subject.metadata.%s = %s", k, s.Metadata[k]))
+		}
+	}
+	if len(rule.Purpose) > 0 {
+		purposes := make([]string, len(rule.Purpose))
+		for i, p := range rule.Purpose {
+			purposes[i] = string(p)
+		}
+		conds = append(conds, "purpose in "+strings.Join(purposes, ", "))
+	}
+	if len(rule.LicensingMode) > 0 {
+		modes := make([]string, len(rule.LicensingMode))
+		for i, m := range rule.LicensingMode {
+			modes[i] = string(m)
+		}
+		conds = append(conds, "licensing_mode in "+strings.Join(modes, ", "))
+	}
+	if rule.Currency != "" {
+		conds = append(conds, "currency = "+rule.Currency)
+	}
+	if rule.MinAmount > 0 {
+		conds = append(conds, fmt.Sprintf("amount >= %d", rule.MinAmount))
+	}
+	if len(conds) == 0 {
+		conds = append(conds, "any request")
+	}
+	return conds
+}
+
+// subjectIDPatterns returns the matcher's ID and IDs patterns.
+func subjectIDPatterns(s *SubjectMatcher) []string {
+	var ids []string
+	if s.ID != "" {
+		ids = append(ids, s.ID)
+	}
+	return append(ids, s.IDs...)
+}
+
+// decisionNode is the DOT node ID for a decision outcome.
+func decisionNode(d Decision) string {
+	return dotQuote("decision_" + string(d))
+}
+
+func decisionColor(d Decision) string {
+	switch d {
+	case Allow:
+		return "palegreen"
+	case Deny:
+		return "lightpink"
+	case Review:
+		return "lightgoldenrod"
+	default:
+		return "lightgray"
+	}
+}
+
+// dotQuote returns s as a DOT quoted string. Newlines become \n line
+// breaks.
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,66 @@
+package policy
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestToDOT(t *testing.T) {
+	policy := &PolicyDocument{
+		Version: PolicyVersion,
+		Name:    `publisher "main"`,
+		Rules: []PolicyRule{
+			{Name: "block-train", Purpose: Purposes{PurposeTrain}, Decision: Deny},
+			{Name: "partners", Subject: &SubjectMatcher{Type: Agent, ID: "partner:*"}, Decision: Allow, Priority: 10},
+			{Name: "paid-crawl", Purpose: Purposes{PurposeCrawl}, MinAmount: 100, Currency: "USD", Decision: Review},
+		},
+		Defaults: &PolicyDefaults{Decision: Deny, Reason: "no rule matched"},
+	}
+	dot := ToDOT(policy)
+
+	if !strings.HasPrefix(dot, `digraph "publisher \"main\"" {`+"\n") || !strings.HasSuffix(dot, "}\n") {
+		t.Fatalf("not a digraph:\n%s", dot)
+	}
+	if strings.Count(dot, "{") != strings.Count(dot, "}") {
+		t.Errorf("unbalanced braces:\n%s", dot)
+	}
+	// Every statement line ends with ';' and has balanced unescaped quotes.
+	unescaped := regexp.MustCompile(`(^|[^\\])"`)
+	lines := strings.Split(strings.TrimSuffix(dot, "}\n"), "\n")
+	for _, line := range lines[1 : len(lines)-1] {
+		if !strings.HasSuffix(line, ";") {
+			t.Errorf("statement %q does not end with ';'", line)
+		}
+		if n := len(unescaped.FindAllString(line, -1)); n%2 != 0 {
+			t.Errorf("statement %q has unbalanced quotes", line)
+		}
+	}
+
+	// Rules appear in evaluation order, chained by "no match" edges.
+	for _, want := range []string{
+		`rule0 [label="1. partners\nsubject.type = agent\nsubject.id ~ partner:*"];`,
+		`rule1 [label="2. block-train\npurpose in train"];`,
+		`rule2 [label="3. paid-crawl\npurpose in crawl\ncurrency = USD\namount >= 100"];`,
+		`start -> rule0;`,
+		`rule0 -> rule1 [label="no match"];`,
+		`rule2 -> default [label="no match"];`,
+		`rule0 -> "decision_allow" [label="match"];`,
+		`default [shape=diamond, label="default\nno rule matched"];`,
+		`default -> "decision_deny";`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("missing %s in:\n%s", want, dot)
+		}
+	}
+	if n := strings.Count(dot, `"decision_deny" [shape`); n != 1 {
+		t.Errorf("decision_deny declared %d times, want 1", n)
+	}
+}
+
+func TestToDOT_NilPolicy(t *testing.T) {
+	dot := ToDOT(nil)
+	if !strings.Contains(dot, "start -> default;") || !strings.Contains(dot, `default -> "decision_deny";`) {
+		t.Errorf("nil policy should fall through to deny:\n%s", dot)
+	}
+}
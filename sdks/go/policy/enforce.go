@@ -22,31 +22,47 @@ type EnforcementResult struct {
 // WWWAuthenticateHeader is the header value for 402 responses.
 const WWWAuthenticateHeader = `PEAC realm="receipt", error="receipt_required"`
 
+// StatusForDecision returns the HTTP status for a policy decision, matching
+// EnforceDecision: allow is 200, deny (and any unknown decision) is 403, and
+// review is 402 until a verified receipt is presented, then 200. It pairs with
+// peac.StatusForError, which covers verification failures (400/401), so a
+// gateway can translate both kinds of outcome uniformly.
+func StatusForDecision(decision Decision, receiptVerified bool) int {
+	switch decision {
+	case Allow:
+		return http.StatusOK
+	case Review:
+		if receiptVerified {
+			return http.StatusOK
+		}
+		return http.StatusPaymentRequired
+	default:
+		return http.StatusForbidden
+	}
+}
+
 // EnforceDecision maps a policy decision to an HTTP response.
 // For review decisions, receiptVerified determines whether access is granted.
 func EnforceDecision(decision Decision, receiptVerified bool) *EnforcementResult {
 	result := &EnforcementResult{
-		Headers: make(http.Header),
+		StatusCode: StatusForDecision(decision, receiptVerified),
+		Headers:    make(http.Header),
 	}
 
 	switch decision {
 	case Allow:
-		result.StatusCode = http.StatusOK
 		result.Allowed = true
 		result.Challenge = false
 
 	case Deny:
-		result.StatusCode = http.StatusForbidden
 		result.Allowed = false
 		result.Challenge = false
 
 	case Review:
 		if receiptVerified {
-			result.StatusCode = http.StatusOK
 			result.Allowed = true
 			result.Challenge = false
 		} else {
-			result.StatusCode = http.StatusPaymentRequired
 			result.Allowed = false
 			result.Challenge = true
 			result.Headers.Set("WWW-Authenticate", WWWAuthenticateHeader)
@@ -54,7 +70,6 @@ func EnforceDecision(decision Decision, receiptVerified bool) *EnforcementResult
 
 	default:
 		// Unknown decision defaults to deny
-		result.StatusCode = http.StatusForbidden
 		result.Allowed = false
 		result.Challenge = false
 	}
@@ -62,13 +77,25 @@ func EnforceDecision(decision Decision, receiptVerified bool) *EnforcementResult
 	return result
 }
 
-// EnforceResult is a convenience function that evaluates and enforces in one step.
+// EnforceResult maps an evaluation result to an HTTP response. It behaves
+// like EnforceDecision, except that an allow or review result carrying
+// ReceiptRequirements answers 402 until a verified receipt is presented,
+// with the requirements in the WWW-Authenticate challenge.
+//
+// receiptVerified is trusted as given: EnforceResult does not inspect the
+// receipt, so pass true only for one that meets the requirements.
+// peac.CheckReceiptRequirements checks verified claims against them.
 func EnforceResult(result *EvaluationResult, receiptVerified bool) *EnforcementResult {
-	return EnforceDecision(result.Decision, receiptVerified)
+	req := result.ReceiptRequirements
+	if req == nil || receiptVerified || (result.Decision != Allow && result.Decision != Review) {
+		return EnforceDecision(result.Decision, receiptVerified)
+	}
+	enforced := EnforceDecision(Review, false)
+	enforced.Headers.Set("WWW-Authenticate", BuildChallenge(req))
+	return enforced
 }
 
 // EvaluateAndEnforce evaluates a policy and returns the enforcement result.
 func EvaluateAndEnforce(policy *PolicyDocument, context *EvaluationContext, receiptVerified bool) *EnforcementResult {
-	result := Evaluate(policy, context)
-	return EnforceDecision(result.Decision, receiptVerified)
+	return EnforceResult(Evaluate(policy, context), receiptVerified)
 }
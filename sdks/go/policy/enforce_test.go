@@ -145,3 +145,67 @@ func TestEvaluateAndEnforce(t *testing.T) {
 		t.Errorf("StatusCode = %d, want %d", result.StatusCode, http.StatusOK)
 	}
 }
+
+func TestEnforceResult_ReceiptRequirements(t *testing.T) {
+	policy := &PolicyDocument{
+		Version: PolicyVersion,
+		Rules: []PolicyRule{{
+			Name:                "paid-training",
+			Purpose:             Purposes{PurposeTrain},
+			Decision:            Allow,
+			ReceiptRequirements: &ReceiptRequirements{MinAmount: 250, Currency: "USD", Purpose: PurposeTrain},
+		}},
+	}
+	context := &EvaluationContext{Purpose: PurposeTrain}
+
+	evalResult := Evaluate(policy, context)
+	if evalResult.ReceiptRequirements != policy.Rules[0].ReceiptRequirements {
+		t.Fatalf("ReceiptRequirements = %+v, want the matched rule's", evalResult.ReceiptRequirements)
+	}
+
+	result := EvaluateAndEnforce(policy, context, false)
+	if result.StatusCode != http.StatusPaymentRequired || result.Allowed || !result.Challenge {
+		t.Errorf("without receipt: status=%d allowed=%v challenge=%v, want 402 challenge", result.StatusCode, result.Allowed, result.Challenge)
+	}
+	want := WWWAuthenticateHeader + `, min_amount="250", currency="USD", purpose="train"`
+	if got := result.Headers.Get("WWW-Authenticate"); got != want {
+		t.Errorf("WWW-Authenticate = %s, want %s", got, want)
+	}
+
+	result = EvaluateAndEnforce(policy, context, true)
+	if result.StatusCode != http.StatusOK || !result.Allowed {
+		t.Errorf("with receipt: status=%d allowed=%v, want 200", result.StatusCode, result.Allowed)
+	}
+}
+
+func TestBuildChallenge(t *testing.T) {
+	if got := BuildChallenge(nil); got != WWWAuthenticateHeader {
+		t.Errorf("BuildChallenge(nil) = %s", got)
+	}
+	if got, want := BuildChallenge(&ReceiptRequirements{Purpose: PurposeCrawl}), WWWAuthenticateHeader+`, purpose="crawl"`; got != want {
+		t.Errorf("BuildChallenge() = %s, want %s", got, want)
+	}
+}
+
+func TestStatusForDecision(t *testing.T) {
+	tests := []struct {
+		decision Decision
+		verified bool
+		want     int
+	}{
+		{Allow, false, http.StatusOK},
+		{Deny, false, http.StatusForbidden},
+		{Deny, true, http.StatusForbidden},
+		{Review, false, http.StatusPaymentRequired},
+		{Review, true, http.StatusOK},
+		{Decision("unknown"), true, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		if got := StatusForDecision(tt.decision, tt.verified); got != tt.want {
+			t.Errorf("StatusForDecision(%s, %v) = %d, want %d", tt.decision, tt.verified, got, tt.want)
+		}
+		if got := EnforceDecision(tt.decision, tt.verified).StatusCode; got != tt.want {
+			t.Errorf("EnforceDecision(%s, %v).StatusCode = %d, want %d", tt.decision, tt.verified, got, tt.want)
+		}
+	}
+}
@@ -1,12 +1,14 @@
 package policy
 
 import (
+	"cmp"
+	"slices"
 	"strings"
 )
 
 // Evaluate evaluates a policy against a context and returns the result.
-// Rules are evaluated in order; the first matching rule wins.
-// If no rule matches, the default decision is used.
+// Rules are evaluated in descending Priority, then array order; the first
+// matching rule wins. If no rule matches, the default decision is used.
 //
 // If policy is nil, returns a deny result with reason ReasonNilPolicy.
 // If context is nil, an empty context is used.
@@ -25,13 +27,20 @@ func Evaluate(policy *PolicyDocument, context *EvaluationContext) *EvaluationRes
 	}
 
 	// Evaluate rules in order - first match wins
-	for _, rule := range policy.Rules {
-		if ruleMatches(&rule, context) {
+	order := evaluationOrder(policy)
+	for i := range policy.Rules {
+		rule := &policy.Rules[i]
+		if order != nil {
+			rule = &policy.Rules[order[i]]
+		}
+		if ruleMatches(rule, context) {
 			return &EvaluationResult{
-				Decision:    rule.Decision,
-				MatchedRule: rule.Name,
-				Reason:      rule.Reason,
-				IsDefault:   false,
+				Decision:            rule.Decision,
+				MatchedRule:         rule.Name,
+				Reason:              rule.Reason,
+				IsDefault:           false,
+				Constraints:         rule.Constraints,
+				ReceiptRequirements: rule.ReceiptRequirements,
 			}
 		}
 	}
@@ -50,6 +59,47 @@ func Evaluate(policy *PolicyDocument, context *EvaluationContext) *EvaluationRes
 	return result
 }
 
+// ruleOrder returns the indices of rules in evaluation order: descending
+// Priority, stable for ties. It returns nil, meaning array order, when no
+// rule sets a priority.
+func ruleOrder(rules []PolicyRule) []int {
+	prioritized := slices.ContainsFunc(rules, func(r PolicyRule) bool { return r.Priority != 0 })
+	if !prioritized {
+		return nil
+	}
+	order := make([]int, len(rules))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(rules[b].Priority, rules[a].Priority)
+	})
+	return order
+}
+
+// evaluationOrder returns the rule order Validate recorded for policy while
+// its Rules slice is the one Validate saw, and sorts the rules otherwise.
+func evaluationOrder(policy *PolicyDocument) []int {
+	if policy.order != nil && len(policy.order) == len(policy.Rules) && &policy.Rules[0] == policy.orderFrom {
+		return policy.order
+	}
+	return ruleOrder(policy.Rules)
+}
+
+// orderedRules returns rules in evaluation order. The input is returned
+// as-is when no rule sets a priority.
+func orderedRules(rules []PolicyRule) []PolicyRule {
+	order := ruleOrder(rules)
+	if order == nil {
+		return rules
+	}
+	ordered := make([]PolicyRule, len(order))
+	for i, j := range order {
+		ordered[i] = rules[j]
+	}
+	return ordered
+}
+
 // ruleMatches checks if a rule matches the given context.
 // All specified constraints must match (AND logic).
 func ruleMatches(rule *PolicyRule, context *EvaluationContext) bool {
@@ -64,7 +114,15 @@ func ruleMatches(rule *PolicyRule, context *EvaluationContext) bool {
 	}
 
 	// Check licensing mode
-	if len(rule.LicensingMode) > 0 && !matchesLicensingMode(context.LicensingMode, rule.LicensingMode) {
+	if len(rule.LicensingMode) > 0 && !matchesLicensingMode(context.licensingModes(), rule.LicensingMode) {
+		return false
+	}
+
+	// Check payment
+	if rule.Currency != "" && context.Currency != rule.Currency {
+		return false
+	}
+	if rule.MinAmount > 0 && context.Amount < rule.MinAmount {
 		return false
 	}
 
@@ -76,7 +134,7 @@ func matchesSubject(subject *Subject, matcher *SubjectMatcher) bool {
 	if subject == nil {
 		// If there's a subject matcher but no subject in context, no match
 		// unless the matcher has no constraints
-		return matcher.Type == "" && len(matcher.Labels) == 0 && matcher.ID == ""
+		return matcher.Type == "" && len(matcher.Labels) == 0 && matcher.ID == "" && len(matcher.IDs) == 0 && len(matcher.Metadata) == 0
 	}
 
 	// Check type
@@ -91,11 +149,18 @@ func matchesSubject(subject *Subject, matcher *SubjectMatcher) bool {
 		}
 	}
 
-	// Check ID pattern
-	if matcher.ID != "" && !matchesIDPattern(subject.ID, matcher.ID) {
+	// Check ID patterns - any of ID and IDs
+	if !matchesAnyIDPattern(subject.ID, matcher) {
 		return false
 	}
 
+	// Check metadata - subject must have ALL required key/value pairs
+	for key, want := range matcher.Metadata {
+		if got, ok := subject.Metadata[key]; !ok || got != want {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -123,6 +188,23 @@ func hasAllLabels(subjectLabels []string, requiredLabels []string) bool {
 	return true
 }
 
+// matchesAnyIDPattern checks if id matches matcher.ID or any of
+// matcher.IDs. A matcher with no ID patterns matches any ID.
+func matchesAnyIDPattern(id string, matcher *SubjectMatcher) bool {
+	if matcher.ID == "" && len(matcher.IDs) == 0 {
+		return true
+	}
+	if matcher.ID != "" && matchesIDPattern(id, matcher.ID) {
+		return true
+	}
+	for _, pattern := range matcher.IDs {
+		if matchesIDPattern(id, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
 // matchesIDPattern checks if an ID matches a pattern.
 // Pattern can be exact match or prefix match with * suffix.
 func matchesIDPattern(id string, pattern string) bool {
@@ -157,18 +239,35 @@ func matchesPurpose(purpose ControlPurpose, allowed Purposes) bool {
 	return false
 }
 
-// matchesLicensingMode checks if a mode matches any of the allowed modes.
-func matchesLicensingMode(mode ControlLicensingMode, allowed LicensingModes) bool {
+// licensingModes returns the context's licensing modes: the single
+// LicensingMode (if set) followed by LicensingModes.
+func (c *EvaluationContext) licensingModes() []ControlLicensingMode {
+	if c.LicensingMode == "" {
+		return c.LicensingModes
+	}
+	if len(c.LicensingModes) == 0 {
+		return []ControlLicensingMode{c.LicensingMode}
+	}
+	modes := make([]ControlLicensingMode, 0, len(c.LicensingModes)+1)
+	modes = append(modes, c.LicensingMode)
+	return append(modes, c.LicensingModes...)
+}
+
+// matchesLicensingMode checks if any of the context's modes is among the
+// allowed modes (non-empty intersection).
+func matchesLicensingMode(modes []ControlLicensingMode, allowed LicensingModes) bool {
 	if len(allowed) == 0 {
 		return true // No constraint means any mode
 	}
-	if mode == "" {
+	if len(modes) == 0 {
 		return false // If allowed is specified but context has no mode, no match
 	}
 
-	for _, m := range allowed {
-		if m == mode {
-			return true
+	for _, mode := range modes {
+		for _, m := range allowed {
+			if m == mode {
+				return true
+			}
 		}
 	}
 	return false
@@ -189,6 +288,20 @@ func RequiresReview(policy *PolicyDocument, context *EvaluationContext) bool {
 	return Evaluate(policy, context).Decision == Review
 }
 
+// AllowedPurposes evaluates the policy for each known purpose (AllPurposes)
+// on behalf of subject and returns the decision per purpose. It is a
+// capability matrix built from Evaluate, so nil policy and nil subject behave
+// exactly as they do there: a nil policy denies every purpose, and a nil
+// subject matches only rules without subject constraints.
+func AllowedPurposes(policy *PolicyDocument, subject *Subject) map[ControlPurpose]Decision {
+	purposes := AllPurposes()
+	decisions := make(map[ControlPurpose]Decision, len(purposes))
+	for _, purpose := range purposes {
+		decisions[purpose] = Evaluate(policy, &EvaluationContext{Subject: subject, Purpose: purpose}).Decision
+	}
+	return decisions
+}
+
 // EvaluateBatch evaluates a policy against multiple contexts.
 func EvaluateBatch(policy *PolicyDocument, contexts []*EvaluationContext) []*EvaluationResult {
 	results := make([]*EvaluationResult, len(contexts))
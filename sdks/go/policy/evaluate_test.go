@@ -215,6 +215,41 @@ func TestEvaluate_AllLabelsRequired(t *testing.T) {
 	}
 }
 
+func TestEvaluate_SubjectMetadata(t *testing.T) {
+	policy := &PolicyDocument{
+		Version: PolicyVersion,
+		Rules: []PolicyRule{
+			{
+				Name:     "enterprise-eu",
+				Subject:  &SubjectMatcher{Metadata: map[string]string{"tier": "enterprise", "region": "eu"}},
+				Decision: Allow,
+			},
+		},
+		Defaults: &PolicyDefaults{Decision: Deny},
+	}
+
+	tests := []struct {
+		name     string
+		subject  *Subject
+		decision Decision
+	}{
+		{"all pairs match", &Subject{Metadata: map[string]string{"tier": "enterprise", "region": "eu", "extra": "x"}}, Allow},
+		{"value mismatch", &Subject{Metadata: map[string]string{"tier": "free", "region": "eu"}}, Deny},
+		{"missing key", &Subject{Metadata: map[string]string{"tier": "enterprise"}}, Deny},
+		{"no metadata", &Subject{Type: Agent}, Deny},
+		{"no subject", nil, Deny},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Evaluate(policy, &EvaluationContext{Subject: tt.subject})
+			if result.Decision != tt.decision {
+				t.Errorf("Decision = %s, want %s", result.Decision, tt.decision)
+			}
+		})
+	}
+}
+
 func TestEvaluate_IDPrefixMatch(t *testing.T) {
 	// eval-007: Wildcard pattern matches ID prefix
 	policy := testPolicy()
@@ -580,3 +615,311 @@ func TestEvaluateBatch_NilPolicy(t *testing.T) {
 		}
 	}
 }
+
+// Licensing mode set tests
+
+func TestEvaluate_LicensingModesOverlap(t *testing.T) {
+	policy := &PolicyDocument{
+		Version: PolicyVersion,
+		Rules: []PolicyRule{
+			{
+				Name:          "allow-pay-per-crawl",
+				LicensingMode: LicensingModes{LicensingPayPerCrawl},
+				Decision:      Allow,
+			},
+		},
+		Defaults: &PolicyDefaults{Decision: Deny},
+	}
+
+	tests := []struct {
+		name string
+		ctx  *EvaluationContext
+		want Decision
+	}{
+		{
+			name: "overlapping set",
+			ctx:  &EvaluationContext{LicensingModes: LicensingModes{LicensingSubscription, LicensingPayPerCrawl}},
+			want: Allow,
+		},
+		{
+			name: "disjoint set",
+			ctx:  &EvaluationContext{LicensingModes: LicensingModes{LicensingSubscription, LicensingPayPerInference}},
+			want: Deny,
+		},
+		{
+			name: "single field plus set",
+			ctx: &EvaluationContext{
+				LicensingMode:  LicensingSubscription,
+				LicensingModes: LicensingModes{LicensingPayPerCrawl},
+			},
+			want: Allow,
+		},
+		{
+			name: "single field only",
+			ctx:  &EvaluationContext{LicensingMode: LicensingPayPerCrawl},
+			want: Allow,
+		},
+		{
+			name: "empty set",
+			ctx:  &EvaluationContext{},
+			want: Deny,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Evaluate(policy, tt.ctx)
+			if result.Decision != tt.want {
+				t.Errorf("Decision = %s, want %s", result.Decision, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluate_Priority(t *testing.T) {
+	agentCrawl := &EvaluationContext{
+		Subject: &Subject{Type: Agent, ID: "internal:bot"},
+		Purpose: PurposeCrawl,
+	}
+
+	tests := []struct {
+		name  string
+		rules []PolicyRule
+		want  string
+	}{
+		{
+			name: "no priorities keeps array order",
+			rules: []PolicyRule{
+				{Name: "deny-agents", Subject: &SubjectMatcher{Type: Agent}, Decision: Deny},
+				{Name: "allow-internal", Subject: &SubjectMatcher{ID: "internal:*"}, Decision: Allow},
+			},
+			want: "deny-agents",
+		},
+		{
+			name: "higher priority wins over earlier rule",
+			rules: []PolicyRule{
+				{Name: "deny-agents", Subject: &SubjectMatcher{Type: Agent}, Decision: Deny},
+				{Name: "allow-internal", Subject: &SubjectMatcher{ID: "internal:*"}, Decision: Allow, Priority: 10},
+			},
+			want: "allow-internal",
+		},
+		{
+			name: "ties fall back to array order",
+			rules: []PolicyRule{
+				{Name: "deny-crawl", Purpose: Purposes{PurposeCrawl}, Decision: Deny},
+				{Name: "allow-internal", Subject: &SubjectMatcher{ID: "internal:*"}, Decision: Allow, Priority: 5},
+				{Name: "review-agents", Subject: &SubjectMatcher{Type: Agent}, Decision: Review, Priority: 5},
+			},
+			want: "allow-internal",
+		},
+		{
+			name: "negative priority sorts after unprioritized",
+			rules: []PolicyRule{
+				{Name: "catch-all", Decision: Deny, Priority: -1},
+				{Name: "allow-crawl", Purpose: Purposes{PurposeCrawl}, Decision: Allow},
+			},
+			want: "allow-crawl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &PolicyDocument{Version: PolicyVersion, Rules: tt.rules}
+			if result := Evaluate(policy, agentCrawl); result.MatchedRule != tt.want {
+				t.Errorf("MatchedRule = %s, want %s", result.MatchedRule, tt.want)
+			}
+			// Same outcome from the order Validate records
+			if err := Validate(policy); err != nil {
+				t.Fatal(err)
+			}
+			if result := Evaluate(policy, agentCrawl); result.MatchedRule != tt.want {
+				t.Errorf("after Validate: MatchedRule = %s, want %s", result.MatchedRule, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluate_RecordedOrderFollowsRules(t *testing.T) {
+	policy := &PolicyDocument{
+		Version: PolicyVersion,
+		Rules: []PolicyRule{
+			{Name: "deny", Decision: Deny},
+			{Name: "allow", Decision: Allow, Priority: 10},
+		},
+	}
+	if err := Validate(policy); err != nil {
+		t.Fatal(err)
+	}
+	if policy.order == nil {
+		t.Fatal("Validate did not record the rule order")
+	}
+
+	// A new Rules slice is sorted afresh rather than read through the
+	// recorded order.
+	policy.Rules = []PolicyRule{
+		{Name: "deny", Decision: Deny, Priority: 10},
+		{Name: "allow", Decision: Allow},
+	}
+	if got := Evaluate(policy, nil).MatchedRule; got != "deny" {
+		t.Errorf("MatchedRule = %s, want deny", got)
+	}
+}
+
+func TestEvaluate_SurfacesConstraints(t *testing.T) {
+	constraints := &Constraints{
+		RateLimit: &RateLimit{WindowSeconds: 3600, Max: 100},
+		Budget:    &Budget{MaxRequests: 1000},
+	}
+	policy := &PolicyDocument{
+		Version: PolicyVersion,
+		Rules: []PolicyRule{
+			{Name: "review-agents", Subject: &SubjectMatcher{Type: Agent}, Decision: Review, Constraints: constraints},
+		},
+		Defaults: &PolicyDefaults{Decision: Allow},
+	}
+
+	result := Evaluate(policy, &EvaluationContext{Subject: &Subject{Type: Agent}})
+	if result.Constraints != constraints {
+		t.Errorf("Constraints = %+v, want matched rule's constraints", result.Constraints)
+	}
+
+	result = Evaluate(policy, &EvaluationContext{Subject: &Subject{Type: Human}})
+	if result.Constraints != nil {
+		t.Errorf("Constraints = %+v, want nil for default decision", result.Constraints)
+	}
+}
+
+func TestEvaluate_ServiceAndUnknownSubjects(t *testing.T) {
+	policy := &PolicyDocument{
+		Version: PolicyVersion,
+		Rules: []PolicyRule{
+			{Name: "services", Subject: &SubjectMatcher{Type: Service}, Decision: Allow},
+			{Name: "unknown", Subject: &SubjectMatcher{Type: Unknown}, Decision: Review},
+		},
+		Defaults: &PolicyDefaults{Decision: Deny},
+	}
+
+	tests := []struct {
+		name        string
+		subject     *Subject
+		wantRule    string
+		wantOutcome Decision
+	}{
+		{"service", &Subject{Type: Service, ID: "svc:scheduler"}, "services", Allow},
+		{"explicit unknown", &Subject{Type: Unknown}, "unknown", Review},
+		{"untyped subject", &Subject{ID: "anon"}, "", Deny},
+		{"no subject", nil, "", Deny},
+		{"agent", &Subject{Type: Agent}, "", Deny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Evaluate(policy, &EvaluationContext{Subject: tt.subject})
+			if result.MatchedRule != tt.wantRule || result.Decision != tt.wantOutcome {
+				t.Errorf("rule=%q decision=%s, want rule=%q decision=%s", result.MatchedRule, result.Decision, tt.wantRule, tt.wantOutcome)
+			}
+		})
+	}
+}
+
+func TestEvaluate_MinAmount(t *testing.T) {
+	policy := &PolicyDocument{
+		Version: PolicyVersion,
+		Rules: []PolicyRule{
+			{Name: "paid-inference", Purpose: Purposes{PurposeInference}, MinAmount: 500, Currency: "USD", Decision: Allow},
+		},
+		Defaults: &PolicyDefaults{Decision: Deny},
+	}
+
+	tests := []struct {
+		name     string
+		amount   int64
+		currency string
+		want     Decision
+	}{
+		{"exact minimum", 500, "USD", Allow},
+		{"above minimum", 1000, "USD", Allow},
+		{"below minimum", 499, "USD", Deny},
+		{"wrong currency", 1000, "EUR", Deny},
+		{"no payment", 0, "", Deny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Evaluate(policy, &EvaluationContext{Purpose: PurposeInference, Amount: tt.amount, Currency: tt.currency})
+			if result.Decision != tt.want {
+				t.Errorf("Decision = %s, want %s", result.Decision, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluate_SubjectIDs(t *testing.T) {
+	policy := &PolicyDocument{
+		Version: PolicyVersion,
+		Rules: []PolicyRule{
+			{Name: "trusted", Subject: &SubjectMatcher{ID: "ops:root", IDs: []string{"internal:*", "partner:*"}}, Decision: Allow},
+		},
+		Defaults: &PolicyDefaults{Decision: Deny},
+	}
+
+	tests := []struct {
+		id   string
+		want Decision
+	}{
+		{"internal:svc-1", Allow},
+		{"partner:acme", Allow},
+		{"ops:root", Allow},
+		{"external:bot", Deny},
+		{"", Deny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			result := Evaluate(policy, &EvaluationContext{Subject: &Subject{ID: tt.id}})
+			if result.Decision != tt.want {
+				t.Errorf("Decision = %s, want %s", result.Decision, tt.want)
+			}
+		})
+	}
+
+	if d := Evaluate(policy, &EvaluationContext{}).Decision; d != Deny {
+		t.Errorf("nil subject = %s, want deny", d)
+	}
+}
+
+func TestAllowedPurposes(t *testing.T) {
+	policy := &PolicyDocument{
+		Version: PolicyVersion,
+		Rules: []PolicyRule{
+			{Name: "agents-crawl-index", Subject: &SubjectMatcher{Type: Agent}, Purpose: Purposes{PurposeCrawl, PurposeIndex}, Decision: Allow},
+			{Name: "training-review", Purpose: Purposes{PurposeTrain}, Decision: Review},
+		},
+		Defaults: &PolicyDefaults{Decision: Deny},
+	}
+
+	got := AllowedPurposes(policy, &Subject{Type: Agent})
+	if len(got) != len(AllPurposes()) {
+		t.Fatalf("got %d purposes, want %d", len(got), len(AllPurposes()))
+	}
+	want := map[ControlPurpose]Decision{
+		PurposeCrawl:  Allow,
+		PurposeIndex:  Allow,
+		PurposeTrain:  Review,
+		PurposeSearch: Deny,
+	}
+	for purpose, decision := range want {
+		if got[purpose] != decision {
+			t.Errorf("%s = %s, want %s", purpose, got[purpose], decision)
+		}
+	}
+
+	// No subject: only subject-less rules can match.
+	if d := AllowedPurposes(policy, nil)[PurposeCrawl]; d != Deny {
+		t.Errorf("nil subject crawl = %s, want deny", d)
+	}
+
+	// Nil policy denies everything, as Evaluate does.
+	for purpose, d := range AllowedPurposes(nil, &Subject{Type: Agent}) {
+		if d != Deny {
+			t.Errorf("nil policy %s = %s, want deny", purpose, d)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package policy
+
+import "fmt"
+
+// Lint codes.
+const (
+	// LintCodeBlocksAll flags a policy with no rules whose default decision
+	// is not allow, so every request is denied or held for review.
+	LintCodeBlocksAll = "L_DEFAULT_BLOCKS_ALL"
+
+	// LintCodeReviewWithoutRequirements flags a review default when no rule
+	// carries receipt requirements, so clients falling through to it get a
+	// 402 that names nothing they can pay for.
+	LintCodeReviewWithoutRequirements = "L_REVIEW_WITHOUT_REQUIREMENTS"
+)
+
+// LintSeverity ranks a LintWarning.
+type LintSeverity string
+
+const (
+	// LintWarn marks a likely misconfiguration.
+	LintWarn LintSeverity = "warning"
+	// LintError marks a configuration that blocks all traffic.
+	LintError LintSeverity = "error"
+)
+
+// LintWarning is a policy misconfiguration that Validate accepts but that
+// likely breaks the endpoint it guards. It is not an error value: a policy
+// with lint findings still evaluates as written.
+type LintWarning struct {
+	Code     string
+	Severity LintSeverity
+	Message  string
+	Field    string
+}
+
+func (w *LintWarning) String() string {
+	if w.Field != "" {
+		return fmt.Sprintf("%s %s: %s (field: %s)", w.Severity, w.Code, w.Message, w.Field)
+	}
+	return fmt.Sprintf("%s %s: %s", w.Severity, w.Code, w.Message)
+}
+
+// Lint checks a policy for misconfigurations beyond what Validate rejects
+// and returns its findings, or nil if there are none. Run Validate first;
+// Lint assumes a well-formed policy and returns nil for a nil one.
+//
+// Checks:
+//   - A policy with no rules and a deny or review default (an absent
+//     default denies) blocks all traffic (LintError)
+//   - A review default with no rule carrying receipt requirements
+//     (LintWarn)
+func Lint(policy *PolicyDocument) []*LintWarning {
+	if policy == nil {
+		return nil
+	}
+	return lintDefaults(policy)
+}
+
+// lintDefaults checks policy.Defaults against the rules.
+func lintDefaults(policy *PolicyDocument) []*LintWarning {
+	decision := Deny
+	if policy.Defaults != nil {
+		decision = policy.Defaults.Decision
+	}
+
+	if len(policy.Rules) == 0 && decision != Allow {
+		return []*LintWarning{{
+			Code:     LintCodeBlocksAll,
+			Severity: LintError,
+			Message:  fmt.Sprintf("policy has no rules and default decision %s blocks every request", decision),
+			Field:    "defaults.decision",
+		}}
+	}
+
+	if decision == Review && !anyReceiptRequirements(policy.Rules) {
+		return []*LintWarning{{
+			Code:     LintCodeReviewWithoutRequirements,
+			Severity: LintWarn,
+			Message:  "default decision is review but no rule sets receipt_requirements, so unmatched requests get a 402 with nothing to satisfy",
+			Field:    "defaults.decision",
+		}}
+	}
+	return nil
+}
+
+func anyReceiptRequirements(rules []PolicyRule) bool {
+	for i := range rules {
+		if rules[i].ReceiptRequirements != nil {
+			return true
+		}
+	}
+	return false
+}
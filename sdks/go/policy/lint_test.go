@@ -0,0 +1,49 @@
+package policy
+
+import "testing"
+
+func TestLint(t *testing.T) {
+	paidRule := PolicyRule{
+		Name:                "paid-crawl",
+		Purpose:             Purposes{PurposeCrawl},
+		Decision:            Allow,
+		ReceiptRequirements: &ReceiptRequirements{MinAmount: 100, Currency: "USD"},
+	}
+	freeRule := PolicyRule{Name: "humans", Subject: &SubjectMatcher{Type: Human}, Decision: Allow}
+
+	tests := []struct {
+		name     string
+		policy   *PolicyDocument
+		wantCode string
+		wantSev  LintSeverity
+	}{
+		{"nil policy", nil, "", ""},
+		{"no rules, allow default", &PolicyDocument{Version: PolicyVersion, Rules: []PolicyRule{}, Defaults: &PolicyDefaults{Decision: Allow}}, "", ""},
+		{"no rules, no defaults", &PolicyDocument{Version: PolicyVersion, Rules: []PolicyRule{}}, LintCodeBlocksAll, LintError},
+		{"no rules, review default", &PolicyDocument{Version: PolicyVersion, Rules: []PolicyRule{}, Defaults: &PolicyDefaults{Decision: Review}}, LintCodeBlocksAll, LintError},
+		{"review default, no requirements", &PolicyDocument{Version: PolicyVersion, Rules: []PolicyRule{freeRule}, Defaults: &PolicyDefaults{Decision: Review}}, LintCodeReviewWithoutRequirements, LintWarn},
+		{"review default with requirements", &PolicyDocument{Version: PolicyVersion, Rules: []PolicyRule{freeRule, paidRule}, Defaults: &PolicyDefaults{Decision: Review}}, "", ""},
+		{"deny default with rules", &PolicyDocument{Version: PolicyVersion, Rules: []PolicyRule{freeRule}, Defaults: &PolicyDefaults{Decision: Deny}}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := Lint(tt.policy)
+			if tt.wantCode == "" {
+				if len(warnings) != 0 {
+					t.Errorf("Lint() = %v, want none", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 || warnings[0].Code != tt.wantCode || warnings[0].Severity != tt.wantSev {
+				t.Fatalf("Lint() = %v, want one %s %s", warnings, tt.wantSev, tt.wantCode)
+			}
+			if warnings[0].Field != "defaults.decision" {
+				t.Errorf("Field = %q, want defaults.decision", warnings[0].Field)
+			}
+			if err := Validate(tt.policy); err != nil {
+				t.Errorf("lint case should still validate: %v", err)
+			}
+		})
+	}
+}
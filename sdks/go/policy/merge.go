@@ -0,0 +1,87 @@
+package policy
+
+import (
+	"fmt"
+)
+
+// Merge composes a policy from team-owned fragments. Rules are concatenated
+// in order, base first, so first-match-wins evaluation over the merged list
+// behaves as if the fragments were one document. A rule whose name was
+// already defined replaces the earlier rule in place, keeping the earlier
+// rule's position. Defaults come from the last document that sets them.
+//
+// Nil overlays are skipped; a nil base is treated as empty. Every document
+// that declares a version must declare the same one. The merged policy is
+// validated before it is returned.
+func Merge(base *PolicyDocument, overlays ...*PolicyDocument) (*PolicyDocument, error) {
+	return merge(false, base, overlays)
+}
+
+// MergeStrict is Merge, but a rule name defined by more than one document is
+// an error instead of an override.
+func MergeStrict(base *PolicyDocument, overlays ...*PolicyDocument) (*PolicyDocument, error) {
+	return merge(true, base, overlays)
+}
+
+func merge(strict bool, base *PolicyDocument, overlays []*PolicyDocument) (*PolicyDocument, error) {
+	docs := append([]*PolicyDocument{base}, overlays...)
+	merged := &PolicyDocument{Rules: []PolicyRule{}}
+	if base != nil {
+		merged.Version = base.Version
+		merged.Name = base.Name
+	}
+
+	index := make(map[string]int)
+	for i, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		if merged.Version == "" {
+			merged.Version = doc.Version
+		}
+		if doc.Version != "" && doc.Version != merged.Version {
+			return nil, &ValidationError{
+				Code:    ErrCodeInvalidPolicyVersion,
+				Message: fmt.Sprintf("version %s does not match %s", doc.Version, merged.Version),
+				Field:   mergeField(i, "version"),
+			}
+		}
+
+		for j, rule := range doc.Rules {
+			at, seen := index[rule.Name]
+			if !seen || rule.Name == "" {
+				// Unnamed rules are kept so Validate can report them.
+				index[rule.Name] = len(merged.Rules)
+				merged.Rules = append(merged.Rules, rule)
+				continue
+			}
+			if strict {
+				return nil, &ValidationError{
+					Code:    ErrCodeInvalidPolicy,
+					Message: fmt.Sprintf("duplicate rule name %q", rule.Name),
+					Field:   mergeField(i, fmt.Sprintf("rules[%d].name", j)),
+				}
+			}
+			merged.Rules[at] = rule
+		}
+
+		if doc.Defaults != nil {
+			defaults := *doc.Defaults
+			merged.Defaults = &defaults
+		}
+	}
+
+	if err := Validate(merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// mergeField names a field of the i-th merged document: "base" or
+// "overlays[i-1]".
+func mergeField(i int, field string) string {
+	if i == 0 {
+		return "base." + field
+	}
+	return fmt.Sprintf("overlays[%d].%s", i-1, field)
+}
@@ -0,0 +1,108 @@
+package policy
+
+import (
+	"testing"
+)
+
+func TestMerge_RuleOrderAndOverride(t *testing.T) {
+	base := &PolicyDocument{
+		Version: PolicyVersion,
+		Name:    "org",
+		Rules: []PolicyRule{
+			{Name: "block-train", Purpose: Purposes{PurposeTrain}, Decision: Deny},
+			{Name: "allow-search", Purpose: Purposes{PurposeSearch}, Decision: Allow},
+		},
+		Defaults: &PolicyDefaults{Decision: Deny},
+	}
+	overlay := &PolicyDocument{
+		Version: PolicyVersion,
+		Rules: []PolicyRule{
+			{Name: "review-crawl", Purpose: Purposes{PurposeCrawl}, Decision: Review},
+			{Name: "block-train", Purpose: Purposes{PurposeTrain}, Decision: Review},
+		},
+	}
+
+	merged, err := Merge(base, overlay)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	var names []string
+	for _, r := range merged.Rules {
+		names = append(names, r.Name)
+	}
+	want := []string{"block-train", "allow-search", "review-crawl"}
+	if len(names) != len(want) {
+		t.Fatalf("rules = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("rules = %v, want %v", names, want)
+		}
+	}
+	if merged.Rules[0].Decision != Review {
+		t.Errorf("overridden rule decision = %s, want review", merged.Rules[0].Decision)
+	}
+	if merged.Name != "org" || merged.Defaults.Decision != Deny {
+		t.Errorf("name/defaults = %q/%v, want org/deny", merged.Name, merged.Defaults)
+	}
+	if len(base.Rules) != 2 || base.Rules[0].Decision != Deny {
+		t.Error("base policy was modified")
+	}
+}
+
+func TestMergeStrict_NameCollision(t *testing.T) {
+	base := &PolicyDocument{Version: PolicyVersion, Rules: []PolicyRule{{Name: "r", Decision: Allow}}}
+	overlay := &PolicyDocument{Version: PolicyVersion, Rules: []PolicyRule{{Name: "other", Decision: Allow}, {Name: "r", Decision: Deny}}}
+
+	_, err := MergeStrict(base, overlay)
+	ve, ok := err.(*ValidationError)
+	if !ok || ve.Field != "overlays[0].rules[1].name" {
+		t.Fatalf("MergeStrict() error = %v, want collision at overlays[0].rules[1].name", err)
+	}
+
+	if _, err := Merge(base, overlay); err != nil {
+		t.Errorf("Merge() error = %v, want later rule to win", err)
+	}
+}
+
+func TestMerge_DefaultsPrecedence(t *testing.T) {
+	doc := func(d *PolicyDefaults) *PolicyDocument {
+		return &PolicyDocument{Version: PolicyVersion, Rules: []PolicyRule{}, Defaults: d}
+	}
+
+	tests := []struct {
+		name string
+		docs []*PolicyDocument
+		want Decision
+	}{
+		{"last non-nil wins", []*PolicyDocument{doc(&PolicyDefaults{Decision: Deny}), doc(&PolicyDefaults{Decision: Review}), doc(nil)}, Review},
+		{"base only", []*PolicyDocument{doc(&PolicyDefaults{Decision: Allow}), doc(nil)}, Allow},
+		{"nil overlays skipped", []*PolicyDocument{doc(&PolicyDefaults{Decision: Deny}), nil, doc(&PolicyDefaults{Decision: Allow})}, Allow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := Merge(tt.docs[0], tt.docs[1:]...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if merged.Defaults == nil || merged.Defaults.Decision != tt.want {
+				t.Errorf("Defaults = %v, want %s", merged.Defaults, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerge_Validates(t *testing.T) {
+	base := &PolicyDocument{Version: PolicyVersion, Rules: []PolicyRule{}}
+
+	if _, err := Merge(base, &PolicyDocument{Version: "peac-policy/9.9"}); err == nil {
+		t.Error("expected version mismatch error")
+	}
+	if _, err := Merge(base, &PolicyDocument{Rules: []PolicyRule{{Name: "bad", Decision: Decision("maybe")}}}); err == nil {
+		t.Error("expected invalid decision error")
+	}
+	if merged, err := Merge(nil, base); err != nil || merged.Version != PolicyVersion {
+		t.Errorf("Merge(nil, base) = %v, %v", merged, err)
+	}
+}
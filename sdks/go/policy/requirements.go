@@ -0,0 +1,94 @@
+package policy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ReceiptRequirements describes the receipt a request must present for a
+// rule's decision to take effect: an allow (or review) with conditions.
+// Until a verified receipt is presented, EnforceResult answers 402 with
+// the requirements as WWW-Authenticate parameters (see BuildChallenge), so
+// the client learns what payment to make before retrying.
+type ReceiptRequirements struct {
+	// MinAmount is the minimum payment, in minor units of Currency, the
+	// receipt must carry (optional). Set it together with Currency.
+	MinAmount int64 `json:"min_amount,omitempty"`
+
+	// Currency is the ISO 4217 code the receipt's payment must be in
+	// (optional).
+	Currency string `json:"currency,omitempty"`
+
+	// Purpose is the purpose the receipt must be issued for (optional): its
+	// purpose_declared claim must equal it.
+	Purpose ControlPurpose `json:"purpose,omitempty"`
+}
+
+// BuildChallenge returns the WWW-Authenticate value for a 402 response:
+// WWWAuthenticateHeader followed by req's fields as auth-params (min_amount,
+// currency, purpose), omitting unset ones. A nil req yields
+// WWWAuthenticateHeader unchanged.
+func BuildChallenge(req *ReceiptRequirements) string {
+	if req == nil {
+		return WWWAuthenticateHeader
+	}
+	var b strings.Builder
+	b.WriteString(WWWAuthenticateHeader)
+	param := func(name, value string) {
+		fmt.Fprintf(&b, ", %s=%s", name, strconv.Quote(value))
+	}
+	if req.MinAmount > 0 {
+		param("min_amount", strconv.FormatInt(req.MinAmount, 10))
+	}
+	if req.Currency != "" {
+		param("currency", req.Currency)
+	}
+	if req.Purpose != "" {
+		param("purpose", string(req.Purpose))
+	}
+	return b.String()
+}
+
+// validateReceiptRequirements validates a rule's receipt requirements. They
+// only make sense on allow and review rules, and a minimum amount needs a
+// currency to be comparable. It reports whether validation should continue.
+func validateReceiptRequirements(req *ReceiptRequirements, decision Decision, field string, r *validationReport) bool {
+	if req == nil {
+		return true
+	}
+	if decision == Deny {
+		if !r.add(&ValidationError{
+			Code:    ErrCodeInvalidPolicy,
+			Message: "receipt requirements are not allowed on a deny rule",
+			Field:   field,
+		}) {
+			return false
+		}
+	}
+	if req.MinAmount < 0 && !r.add(negativeLimitError(field+".min_amount")) {
+		return false
+	}
+	if req.MinAmount > 0 && req.Currency == "" {
+		if !r.add(&ValidationError{
+			Code:    ErrCodeInvalidPolicy,
+			Message: "min_amount requires currency",
+			Field:   field + ".currency",
+		}) {
+			return false
+		}
+	}
+	if req.Currency != "" && !isCurrencyCode(req.Currency) {
+		if !r.add(&ValidationError{
+			Code:    ErrCodeInvalidPolicy,
+			Message: fmt.Sprintf("invalid currency: %s (must be an ISO 4217 code)", req.Currency),
+			Field:   field + ".currency",
+		}) {
+			return false
+		}
+	}
+	if req.Purpose != "" && !r.add(validatePurpose(req.Purpose, field+".purpose")) {
+		return false
+	}
+	return true
+}
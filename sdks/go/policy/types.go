@@ -21,6 +21,15 @@ const (
 	Human SubjectType = "human"
 	Agent SubjectType = "agent"
 	Org   SubjectType = "org"
+
+	// Service is a non-human, non-agent machine caller such as an internal
+	// service or scheduler.
+	Service SubjectType = "service"
+
+	// Unknown is a caller whose type could not be determined. It must be
+	// set explicitly: a matcher with Type Unknown does not match a subject
+	// that leaves Type empty.
+	Unknown SubjectType = "unknown"
 )
 
 // ControlPurpose represents the intended purpose of access.
@@ -36,6 +45,14 @@ const (
 	PurposeSearch    ControlPurpose = "search"
 )
 
+// AllPurposes returns every known ControlPurpose, in declaration order.
+func AllPurposes() []ControlPurpose {
+	return []ControlPurpose{
+		PurposeCrawl, PurposeIndex, PurposeTrain, PurposeInference,
+		PurposeAiInput, PurposeAiIndex, PurposeSearch,
+	}
+}
+
 // ControlLicensingMode represents the licensing arrangement.
 type ControlLicensingMode string
 
@@ -69,8 +86,15 @@ type PolicyDocument struct {
 	// Defaults specifies fallback values when no rule matches.
 	Defaults *PolicyDefaults `json:"defaults,omitempty"`
 
-	// Rules are evaluated in order; first match wins.
+	// Rules are evaluated in order; first match wins. Rules with a higher
+	// Priority are considered first (see PolicyRule.Priority).
 	Rules []PolicyRule `json:"rules"`
+
+	// order is the evaluation order of Rules as indices, recorded by
+	// Validate for the Rules slice starting at orderFrom. Nil means it was
+	// not recorded or no rule sets a priority.
+	order     []int
+	orderFrom *PolicyRule
 }
 
 // PolicyDefaults specifies default decision when no rule matches.
@@ -104,6 +128,30 @@ type PolicyRule struct {
 
 	// Reason explains why this decision was made.
 	Reason string `json:"reason,omitempty"`
+
+	// Priority orders evaluation independently of array position: rules are
+	// considered in descending priority, with ties kept in array order.
+	// Unprioritized rules have priority 0, so a policy that sets no
+	// priorities is evaluated in exact array order.
+	Priority int `json:"priority,omitempty"`
+
+	// MinAmount is the minimum payment, in minor units, the request must
+	// carry for this rule to match (optional). Minor units are only
+	// comparable within a currency, so set it together with Currency.
+	MinAmount int64 `json:"min_amount,omitempty"`
+
+	// Currency is the ISO 4217 code the request's payment must be in for
+	// this rule to match (optional).
+	Currency string `json:"currency,omitempty"`
+
+	// Constraints are the limits attached to this rule's decision (optional).
+	// They are surfaced on EvaluationResult when the rule matches.
+	Constraints *Constraints `json:"constraints,omitempty"`
+
+	// ReceiptRequirements describe the receipt an allow or review decision
+	// is conditional on (optional). They are surfaced on EvaluationResult
+	// when the rule matches.
+	ReceiptRequirements *ReceiptRequirements `json:"receipt_requirements,omitempty"`
 }
 
 // SubjectMatcher specifies constraints for matching a subject.
@@ -120,6 +168,15 @@ type SubjectMatcher struct {
 	// Supports prefix matching with * (e.g., "internal:*").
 	// If omitted, matches any ID.
 	ID string `json:"id,omitempty"`
+
+	// IDs are alternative ID patterns, in the same syntax as ID. The subject
+	// matches if its ID matches any of them or ID (OR semantics), so one
+	// rule can cover several prefixes (e.g. "internal:*", "partner:*").
+	IDs []string `json:"ids,omitempty"`
+
+	// Metadata key/value pairs the subject must have (ALL required, exact
+	// match). If omitted, matches any metadata.
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // Subject represents a request subject for evaluation.
@@ -132,6 +189,9 @@ type Subject struct {
 
 	// ID of the subject.
 	ID string `json:"id,omitempty"`
+
+	// Metadata associated with the subject (e.g. tier, region).
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // EvaluationContext contains the context for policy evaluation.
@@ -144,6 +204,17 @@ type EvaluationContext struct {
 
 	// LicensingMode of the request.
 	LicensingMode ControlLicensingMode `json:"licensing_mode,omitempty"`
+
+	// LicensingModes lists additional licensing modes the requester holds.
+	// A rule matches if any mode here or in LicensingMode is allowed by it.
+	LicensingModes LicensingModes `json:"licensing_modes,omitempty"`
+
+	// Amount is the payment the request carries, in minor units of
+	// Currency (e.g. from a verified record's commerce extension).
+	Amount int64 `json:"amount,omitempty"`
+
+	// Currency is the ISO 4217 code of Amount.
+	Currency string `json:"currency,omitempty"`
 }
 
 // EvaluationResult contains the result of policy evaluation.
@@ -159,6 +230,13 @@ type EvaluationResult struct {
 
 	// IsDefault indicates whether the default was applied.
 	IsDefault bool `json:"is_default"`
+
+	// Constraints are the matched rule's constraints (nil if none or default).
+	Constraints *Constraints `json:"constraints,omitempty"`
+
+	// ReceiptRequirements are the matched rule's receipt requirements (nil
+	// if none or default).
+	ReceiptRequirements *ReceiptRequirements `json:"receipt_requirements,omitempty"`
 }
 
 // Purposes represents one or more purposes (for JSON unmarshaling).
@@ -1,7 +1,9 @@
 package policy
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 )
 
 // ValidationError represents a policy validation error.
@@ -19,7 +21,8 @@ func (e *ValidationError) Error() string {
 }
 
 // Validate validates a policy document.
-// Returns nil if valid, or a ValidationError if invalid.
+// Returns nil if valid, or the first ValidationError if invalid. Use
+// ValidateAll to collect every error.
 //
 // Validates:
 //   - Policy is not nil
@@ -27,102 +30,225 @@ func (e *ValidationError) Error() string {
 //   - Rules array is present
 //   - All rules have names and valid decisions
 //   - All enum values (SubjectType, Purpose, LicensingMode) are known
+//   - Rule constraints carry positive limits
+//   - Receipt requirements are on allow or review rules and well formed
+//
+// Validate and ValidateAll also record the rules' evaluation order on the
+// document, so Evaluate does not sort prioritized rules on every call.
+// Validate again after changing a rule's Priority in place.
 func Validate(policy *PolicyDocument) error {
+	r := &validationReport{}
+	validatePolicy(policy, r)
+	if len(r.errs) == 0 {
+		return nil
+	}
+	return r.errs[0]
+}
+
+// ValidateAll validates a policy document like Validate but, instead of
+// stopping at the first problem, collects every error with its field path,
+// in document order. It returns nil if the policy is valid.
+func ValidateAll(policy *PolicyDocument) []*ValidationError {
+	r := &validationReport{all: true}
+	validatePolicy(policy, r)
+	return r.errs
+}
+
+// validationReport accumulates validation errors. Unless all is set, it
+// asks callers to stop after the first one.
+type validationReport struct {
+	errs []*ValidationError
+	all  bool
+}
+
+// add records err if non-nil and reports whether validation should continue.
+// An error that is not a *ValidationError is recorded as an invalid policy.
+func (r *validationReport) add(err error) bool {
+	if err == nil {
+		return true
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		verr = &ValidationError{Code: ErrCodeInvalidPolicy, Message: err.Error()}
+	}
+	r.errs = append(r.errs, verr)
+	return r.all
+}
+
+func validatePolicy(policy *PolicyDocument, r *validationReport) {
 	// Guard against nil policy
 	if policy == nil {
-		return &ValidationError{
+		r.add(&ValidationError{
 			Code:    ErrCodeInvalidPolicy,
 			Message: "policy is nil",
-		}
+		})
+		return
+	}
+
+	// Record the evaluation order once for Evaluate
+	policy.order, policy.orderFrom = ruleOrder(policy.Rules), nil
+	if policy.order != nil {
+		policy.orderFrom = &policy.Rules[0]
 	}
 
 	// Check version
 	if policy.Version == "" {
-		return &ValidationError{
+		if !r.add(&ValidationError{
 			Code:    ErrCodeInvalidPolicy,
 			Message: "version is required",
 			Field:   "version",
+		}) {
+			return
 		}
-	}
-
-	if policy.Version != PolicyVersion {
-		return &ValidationError{
+	} else if policy.Version != PolicyVersion {
+		if !r.add(&ValidationError{
 			Code:    ErrCodeInvalidPolicyVersion,
 			Message: fmt.Sprintf("unsupported version: %s (expected %s)", policy.Version, PolicyVersion),
 			Field:   "version",
+		}) {
+			return
 		}
 	}
 
 	// Check rules array exists
 	if policy.Rules == nil {
-		return &ValidationError{
+		if !r.add(&ValidationError{
 			Code:    ErrCodeInvalidPolicy,
 			Message: "rules is required",
 			Field:   "rules",
+		}) {
+			return
 		}
 	}
 
 	// Validate each rule
-	for i, rule := range policy.Rules {
-		if err := validateRule(&rule, i); err != nil {
-			return err
+	for i := range policy.Rules {
+		if !validateRule(&policy.Rules[i], i, r) {
+			return
 		}
 	}
 
 	// Validate defaults if present
 	if policy.Defaults != nil {
-		if err := validateDecision(policy.Defaults.Decision, "defaults.decision"); err != nil {
-			return err
-		}
+		r.add(validateDecision(policy.Defaults.Decision, "defaults.decision"))
 	}
-
-	return nil
 }
 
-// validateRule validates a single policy rule.
-func validateRule(rule *PolicyRule, index int) error {
+// validateRule validates a single policy rule. It reports whether
+// validation should continue.
+func validateRule(rule *PolicyRule, index int, r *validationReport) bool {
 	fieldPrefix := fmt.Sprintf("rules[%d]", index)
 
 	// Check name
 	if rule.Name == "" {
-		return &ValidationError{
+		if !r.add(&ValidationError{
 			Code:    ErrCodeInvalidPolicy,
 			Message: "rule name is required",
 			Field:   fieldPrefix + ".name",
+		}) {
+			return false
 		}
 	}
 
 	// Check decision
-	if err := validateDecision(rule.Decision, fieldPrefix+".decision"); err != nil {
-		return err
+	if !r.add(validateDecision(rule.Decision, fieldPrefix+".decision")) {
+		return false
 	}
 
 	// Validate subject matcher enums
 	if rule.Subject != nil {
-		if err := validateSubjectType(rule.Subject.Type, fieldPrefix+".subject.type"); err != nil {
-			return err
+		if !r.add(validateSubjectType(rule.Subject.Type, fieldPrefix+".subject.type")) {
+			return false
+		}
+		for i, pattern := range rule.Subject.IDs {
+			if !r.add(validateIDPattern(pattern, fmt.Sprintf("%s.subject.ids[%d]", fieldPrefix, i))) {
+				return false
+			}
+		}
+		if _, ok := rule.Subject.Metadata[""]; ok {
+			if !r.add(&ValidationError{
+				Code:    ErrCodeInvalidPolicy,
+				Message: "subject metadata key must not be empty",
+				Field:   fieldPrefix + ".subject.metadata",
+			}) {
+				return false
+			}
 		}
 	}
 
 	// Validate purposes
 	for i, p := range rule.Purpose {
 		field := fmt.Sprintf("%s.purpose[%d]", fieldPrefix, i)
-		if err := validatePurpose(p, field); err != nil {
-			return err
+		if !r.add(validatePurpose(p, field)) {
+			return false
 		}
 	}
 
 	// Validate licensing modes
 	for i, m := range rule.LicensingMode {
 		field := fmt.Sprintf("%s.licensing_mode[%d]", fieldPrefix, i)
-		if err := validateLicensingMode(m, field); err != nil {
-			return err
+		if !r.add(validateLicensingMode(m, field)) {
+			return false
+		}
+	}
+
+	// Validate payment
+	if rule.MinAmount < 0 && !r.add(negativeLimitError(fieldPrefix+".min_amount")) {
+		return false
+	}
+	if rule.Currency != "" && !isCurrencyCode(rule.Currency) {
+		if !r.add(&ValidationError{
+			Code:    ErrCodeInvalidPolicy,
+			Message: fmt.Sprintf("invalid currency: %s (must be an ISO 4217 code)", rule.Currency),
+			Field:   fieldPrefix + ".currency",
+		}) {
+			return false
 		}
 	}
 
+	// Validate constraints
+	if !validateConstraints(rule.Constraints, fieldPrefix+".constraints", r) {
+		return false
+	}
+
+	// Validate receipt requirements
+	return validateReceiptRequirements(rule.ReceiptRequirements, rule.Decision, fieldPrefix+".receipt_requirements", r)
+}
+
+// validateIDPattern validates a subject ID pattern: non-empty, with * only
+// as a trailing wildcard.
+func validateIDPattern(pattern, field string) error {
+	if pattern == "" {
+		return &ValidationError{
+			Code:    ErrCodeInvalidPolicy,
+			Message: "subject ID pattern cannot be empty",
+			Field:   field,
+		}
+	}
+	if i := strings.Index(pattern, "*"); i >= 0 && i != len(pattern)-1 {
+		return &ValidationError{
+			Code:    ErrCodeInvalidPolicy,
+			Message: fmt.Sprintf("invalid subject ID pattern: %s (* is only allowed as a trailing wildcard)", pattern),
+			Field:   field,
+		}
+	}
 	return nil
 }
 
+// isCurrencyCode reports whether s has the ISO 4217 alphabetic code shape:
+// three uppercase ASCII letters.
+func isCurrencyCode(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
 // validateDecision validates a decision value.
 func validateDecision(decision Decision, field string) error {
 	switch decision {
@@ -147,12 +273,12 @@ func validateDecision(decision Decision, field string) error {
 // Empty is allowed (means any type).
 func validateSubjectType(st SubjectType, field string) error {
 	switch st {
-	case "", Human, Agent, Org:
+	case "", Human, Agent, Org, Service, Unknown:
 		return nil
 	default:
 		return &ValidationError{
 			Code:    ErrCodeInvalidPolicyEnum,
-			Message: fmt.Sprintf("unknown subject type: %s (must be human, agent, or org)", st),
+			Message: fmt.Sprintf("unknown subject type: %s (must be human, agent, org, service, or unknown)", st),
 			Field:   field,
 		}
 	}
@@ -1,6 +1,9 @@
 package policy
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -532,6 +535,20 @@ func TestValidate_ValidEnumValues(t *testing.T) {
 					Type: Org,
 				},
 			},
+			{
+				Name:     "test-service",
+				Decision: Allow,
+				Subject: &SubjectMatcher{
+					Type: Service,
+				},
+			},
+			{
+				Name:     "test-unknown",
+				Decision: Deny,
+				Subject: &SubjectMatcher{
+					Type: Unknown,
+				},
+			},
 		},
 	}
 
@@ -568,3 +585,227 @@ func TestValidate_MultipleUnknownEnums(t *testing.T) {
 		t.Errorf("error field = %s, want rules[0].purpose[0]", ve.Field)
 	}
 }
+
+func TestValidate_Constraints(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints *Constraints
+		wantField   string
+	}{
+		{"valid", &Constraints{RateLimit: &RateLimit{WindowSeconds: 60, Max: 10, RetryAfterSeconds: 30}, Budget: &Budget{MaxTokens: 5000}}, ""},
+		{"zero window", &Constraints{RateLimit: &RateLimit{Max: 10}}, "rules[0].constraints.rate_limit.window_s"},
+		{"zero max", &Constraints{RateLimit: &RateLimit{WindowSeconds: 60}}, "rules[0].constraints.rate_limit.max"},
+		{"negative budget", &Constraints{Budget: &Budget{MaxRequests: -1}}, "rules[0].constraints.budget.max_requests"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &PolicyDocument{
+				Version: PolicyVersion,
+				Rules:   []PolicyRule{{Name: "r", Decision: Allow, Constraints: tt.constraints}},
+			}
+			err := Validate(policy)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			ve, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+			if ve.Field != tt.wantField {
+				t.Errorf("Field = %s, want %s", ve.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidate_Payment(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      PolicyRule
+		wantField string
+	}{
+		{"valid", PolicyRule{Name: "r", Decision: Allow, MinAmount: 500, Currency: "USD"}, ""},
+		{"negative amount", PolicyRule{Name: "r", Decision: Allow, MinAmount: -1}, "rules[0].min_amount"},
+		{"lowercase currency", PolicyRule{Name: "r", Decision: Allow, Currency: "usd"}, "rules[0].currency"},
+		{"long currency", PolicyRule{Name: "r", Decision: Allow, Currency: "USDC"}, "rules[0].currency"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&PolicyDocument{Version: PolicyVersion, Rules: []PolicyRule{tt.rule}})
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			ve, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+			if ve.Field != tt.wantField {
+				t.Errorf("Field = %s, want %s", ve.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidate_ReceiptRequirements(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      PolicyRule
+		wantField string
+	}{
+		{"valid", PolicyRule{Name: "r", Decision: Allow, ReceiptRequirements: &ReceiptRequirements{MinAmount: 100, Currency: "USD", Purpose: PurposeTrain}}, ""},
+		{"purpose only on review", PolicyRule{Name: "r", Decision: Review, ReceiptRequirements: &ReceiptRequirements{Purpose: PurposeCrawl}}, ""},
+		{"deny rule", PolicyRule{Name: "r", Decision: Deny, ReceiptRequirements: &ReceiptRequirements{}}, "rules[0].receipt_requirements"},
+		{"negative amount", PolicyRule{Name: "r", Decision: Allow, ReceiptRequirements: &ReceiptRequirements{MinAmount: -1}}, "rules[0].receipt_requirements.min_amount"},
+		{"amount without currency", PolicyRule{Name: "r", Decision: Allow, ReceiptRequirements: &ReceiptRequirements{MinAmount: 100}}, "rules[0].receipt_requirements.currency"},
+		{"bad currency", PolicyRule{Name: "r", Decision: Allow, ReceiptRequirements: &ReceiptRequirements{Currency: "usd"}}, "rules[0].receipt_requirements.currency"},
+		{"unknown purpose", PolicyRule{Name: "r", Decision: Allow, ReceiptRequirements: &ReceiptRequirements{Purpose: "resell"}}, "rules[0].receipt_requirements.purpose"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&PolicyDocument{Version: PolicyVersion, Rules: []PolicyRule{tt.rule}})
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			ve, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+			if ve.Field != tt.wantField {
+				t.Errorf("Field = %s, want %s", ve.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidate_SubjectIDs(t *testing.T) {
+	tests := []struct {
+		name      string
+		ids       []string
+		wantField string
+	}{
+		{"valid", []string{"internal:*", "partner:acme"}, ""},
+		{"empty entry", []string{"internal:*", ""}, "rules[0].subject.ids[1]"},
+		{"inner wildcard", []string{"in*ternal"}, "rules[0].subject.ids[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&PolicyDocument{
+				Version: PolicyVersion,
+				Rules:   []PolicyRule{{Name: "r", Decision: Allow, Subject: &SubjectMatcher{IDs: tt.ids}}},
+			})
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			ve, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+			if ve.Field != tt.wantField {
+				t.Errorf("Field = %s, want %s", ve.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidate_EmptySubjectMetadataKey(t *testing.T) {
+	policy := &PolicyDocument{
+		Version: PolicyVersion,
+		Rules: []PolicyRule{
+			{
+				Name:     "test-rule",
+				Decision: Allow,
+				Subject: &SubjectMatcher{
+					Metadata: map[string]string{"": "enterprise"},
+				},
+			},
+		},
+	}
+
+	err := Validate(policy)
+	ve, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("error = %v, want *ValidationError", err)
+	}
+	if ve.Code != ErrCodeInvalidPolicy {
+		t.Errorf("error code = %s, want %s", ve.Code, ErrCodeInvalidPolicy)
+	}
+	if ve.Field != "rules[0].subject.metadata" {
+		t.Errorf("error field = %s, want rules[0].subject.metadata", ve.Field)
+	}
+}
+
+func TestValidateAll_CollectsEveryError(t *testing.T) {
+	policy := &PolicyDocument{
+		Version: "peac-policy/9.9",
+		Rules: []PolicyRule{
+			{Name: "ok", Decision: Allow},
+			{Decision: Decision("maybe"), Purpose: Purposes{PurposeCrawl, ControlPurpose("mine")}},
+			{Name: "bad-limits", Decision: Deny, Constraints: &Constraints{RateLimit: &RateLimit{WindowSeconds: 0, Max: -1}}},
+		},
+		Defaults: &PolicyDefaults{},
+	}
+
+	errs := ValidateAll(policy)
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	want := []string{
+		"version",
+		"rules[1].name",
+		"rules[1].decision",
+		"rules[1].purpose[1]",
+		"rules[2].constraints.rate_limit.window_s",
+		"rules[2].constraints.rate_limit.max",
+		"defaults.decision",
+	}
+	if strings.Join(fields, ",") != strings.Join(want, ",") {
+		t.Errorf("fields = %v, want %v", fields, want)
+	}
+
+	// Validate still reports only the first error.
+	if err := Validate(policy); err == nil || err.Error() != errs[0].Error() {
+		t.Errorf("Validate() = %v, want first error %v", err, errs[0])
+	}
+}
+
+func TestValidateAll_Valid(t *testing.T) {
+	if errs := ValidateAll(&PolicyDocument{Version: PolicyVersion, Rules: []PolicyRule{}}); errs != nil {
+		t.Errorf("ValidateAll() = %v, want nil", errs)
+	}
+	if errs := ValidateAll(nil); len(errs) != 1 {
+		t.Errorf("ValidateAll(nil) returned %d errors, want 1", len(errs))
+	}
+}
+
+func TestValidationReport_Add(t *testing.T) {
+	r := &validationReport{all: true}
+	want := &ValidationError{Code: ErrCodeInvalidPolicyEnum, Message: "bad purpose", Field: "rules[0].purpose"}
+	r.add(fmt.Errorf("rule: %w", want))
+	r.add(errors.New("unexpected"))
+	if len(r.errs) != 2 {
+		t.Fatalf("errs = %v, want 2", r.errs)
+	}
+	if r.errs[0] != want {
+		t.Errorf("wrapped error recorded as %v, want %v", r.errs[0], want)
+	}
+	if got := r.errs[1]; got.Code != ErrCodeInvalidPolicy || got.Message != "unexpected" {
+		t.Errorf("plain error recorded as %v, want %s", got, ErrCodeInvalidPolicy)
+	}
+}
@@ -0,0 +1,82 @@
+package peac
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ProblemContentType is the RFC 9457 media type for problem details.
+const ProblemContentType = "application/problem+json"
+
+// problemTypeBase prefixes the type URI derived from an error code.
+const problemTypeBase = "https://www.peacprotocol.org/errors/"
+
+// Problem is an RFC 9457 problem details object. Extensions are serialized
+// as top-level members alongside the standard ones; an extension cannot
+// override a standard member.
+type Problem struct {
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+
+	// Extensions holds additional members (e.g. "peac_error").
+	Extensions map[string]any
+}
+
+// NewProblem creates a problem for an error code. The type URI is derived
+// from code and the title is the code itself.
+func NewProblem(status int, code, detail string) *Problem {
+	return &Problem{
+		Type:   problemTypeBase + strings.ToLower(code),
+		Title:  code,
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// ProblemFromError creates a problem for a PEAC error, using its HTTP status
+// and carrying its details under the "peac_error" extension member.
+func ProblemFromError(err *PEACError) *Problem {
+	p := NewProblem(err.HTTPStatus(), string(err.Code), err.Message)
+	if len(err.Details) > 0 {
+		p.Extensions = map[string]any{"peac_error": err.Details}
+	}
+	return p
+}
+
+// MarshalJSON flattens Extensions into the problem object. Empty standard
+// members are omitted.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+	for k, v := range map[string]string{"type": p.Type, "title": p.Title, "detail": p.Detail, "instance": p.Instance} {
+		if v != "" {
+			members[k] = v
+		} else {
+			delete(members, k)
+		}
+	}
+	if p.Status != 0 {
+		members["status"] = p.Status
+	} else {
+		delete(members, "status")
+	}
+	return json.Marshal(members)
+}
+
+// WriteResponse writes the problem to w with the problem+json content type
+// and its status code (500 when Status is unset).
+func (p *Problem) WriteResponse(w http.ResponseWriter) error {
+	status := p.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,100 @@
+package peac
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProblemMarshalJSON(t *testing.T) {
+	p := NewProblem(http.StatusBadRequest, "E_INVALID_SIGNATURE", "bad sig")
+	p.Instance = "req-1"
+	p.Extensions = map[string]any{
+		"peac_error": map[string]any{"key_id": "k1"},
+		"status":     999,
+		"title":      "overridden",
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	tests := []struct {
+		key  string
+		want any
+	}{
+		{"type", "https://www.peacprotocol.org/errors/e_invalid_signature"},
+		{"title", "E_INVALID_SIGNATURE"},
+		{"status", float64(400)},
+		{"detail", "bad sig"},
+		{"instance", "req-1"},
+	}
+	for _, tt := range tests {
+		if got[tt.key] != tt.want {
+			t.Errorf("%s = %v, want %v", tt.key, got[tt.key], tt.want)
+		}
+	}
+	if ext, ok := got["peac_error"].(map[string]any); !ok || ext["key_id"] != "k1" {
+		t.Errorf("peac_error = %v, want key_id k1", got["peac_error"])
+	}
+}
+
+func TestProblemMarshalJSONOmitsEmpty(t *testing.T) {
+	p := &Problem{Title: "E_X", Extensions: map[string]any{"detail": "ext"}}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"title":"E_X"}` {
+		t.Errorf("Marshal = %s, want {\"title\":\"E_X\"}", data)
+	}
+}
+
+func TestProblemFromError(t *testing.T) {
+	err := NewPEACError(ErrInvalidSignature, "bad sig").WithDetail("key_id", "k1")
+	p := ProblemFromError(err)
+	if p.Status != err.HTTPStatus() {
+		t.Errorf("Status = %d, want %d", p.Status, err.HTTPStatus())
+	}
+	if p.Title != string(ErrInvalidSignature) {
+		t.Errorf("Title = %q, want %q", p.Title, ErrInvalidSignature)
+	}
+	if _, ok := p.Extensions["peac_error"]; !ok {
+		t.Error("Extensions missing peac_error")
+	}
+
+	if p := ProblemFromError(NewPEACError(ErrInvalidSignature, "x")); p.Extensions != nil {
+		t.Errorf("Extensions = %v, want nil without details", p.Extensions)
+	}
+}
+
+func TestProblemWriteResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   int
+	}{
+		{"explicit", http.StatusTooManyRequests, http.StatusTooManyRequests},
+		{"unset", 0, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if err := (&Problem{Title: "E_X", Status: tt.status}).WriteResponse(rec); err != nil {
+				t.Fatalf("WriteResponse failed: %v", err)
+			}
+			if rec.Code != tt.want {
+				t.Errorf("Code = %d, want %d", rec.Code, tt.want)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != ProblemContentType {
+				t.Errorf("Content-Type = %q, want %q", ct, ProblemContentType)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package peac
+
+import (
+	"fmt"
+	"sync"
+)
+
+// RailEvidenceValidator checks rail-specific evidence before issuance. It
+// receives the full extension map, so it can inspect the commerce entry
+// (reference, asset, ...) as well as any vendor extension the rail defines.
+// Return an error naming the offending field.
+type RailEvidenceValidator func(extensions map[string]any) error
+
+// railValidators holds validators registered with RegisterRail, keyed by
+// payment_rail. Guarded by railValidatorsMu so rails can be registered at
+// startup while issuance runs concurrently.
+var (
+	railValidatorsMu sync.RWMutex
+	railValidators   = map[string]RailEvidenceValidator{}
+)
+
+// RegisterRail registers validator for records whose commerce extension has
+// payment_rail equal to name. Issue (and ValidateIssueOptions) then rejects
+// records that fail it with ErrCodeInvalidEvidence. Unregistered rails only
+// get the generic evidence limits. Registering a name again replaces its
+// validator. Safe for concurrent use.
+func RegisterRail(name string, validator RailEvidenceValidator) {
+	railValidatorsMu.Lock()
+	defer railValidatorsMu.Unlock()
+	if validator == nil {
+		delete(railValidators, name)
+		return
+	}
+	railValidators[name] = validator
+}
+
+// UnregisterRail removes the validator registered for name, if any.
+func UnregisterRail(name string) {
+	RegisterRail(name, nil)
+}
+
+// validateRailEvidence runs the registered validator for the commerce
+// extension's payment_rail, if there is one.
+func validateRailEvidence(extensions map[string]any) error {
+	commerce, ok := extensions[CommerceExtensionKey].(map[string]any)
+	if !ok {
+		return nil
+	}
+	rail, _ := commerce["payment_rail"].(string)
+
+	railValidatorsMu.RLock()
+	validator := railValidators[rail]
+	railValidatorsMu.RUnlock()
+	if validator == nil {
+		return nil
+	}
+
+	if err := validator(extensions); err != nil {
+		return &IssueError{
+			Code:    ErrCodeInvalidEvidence,
+			Message: fmt.Sprintf("rail %q evidence: %v", rail, err),
+			Field:   "Extensions",
+			Details: map[string]any{"rail": rail},
+		}
+	}
+	return nil
+}
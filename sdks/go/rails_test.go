@@ -0,0 +1,58 @@
+package peac
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegisterRail(t *testing.T) {
+	RegisterRail("stripe", func(ext map[string]any) error {
+		commerce := ext[CommerceExtensionKey].(map[string]any)
+		if commerce["reference"] == nil {
+			return errors.New("reference (charge_id) is required")
+		}
+		return nil
+	})
+	t.Cleanup(func() { UnregisterRail("stripe") })
+
+	commerce := func(rail string, reference any) map[string]any {
+		m := map[string]any{"payment_rail": rail, "amount_minor": "1000", "currency": "USD"}
+		if reference != nil {
+			m["reference"] = reference
+		}
+		return map[string]any{CommerceExtensionKey: m}
+	}
+
+	tests := []struct {
+		name    string
+		ext     map[string]any
+		wantErr bool
+	}{
+		{"registered rail valid", commerce("stripe", "ch_123"), false},
+		{"registered rail invalid", commerce("stripe", nil), true},
+		{"unregistered rail", commerce("x402", nil), false},
+		{"no commerce extension", map[string]any{"com.example/other": "x"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Issue(IssueOptions{
+				Iss:        "https://example.com",
+				Kind:       KindEvidence,
+				Type:       "org.peacprotocol/payment",
+				SigningKey: testSigningKey(t),
+				Extensions: tt.ext,
+			})
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("Issue() error = %v", err)
+				}
+				return
+			}
+			var ie *IssueError
+			if !errors.As(err, &ie) || ie.Code != ErrCodeInvalidEvidence {
+				t.Errorf("err = %v, want %s", err, ErrCodeInvalidEvidence)
+			}
+		})
+	}
+}
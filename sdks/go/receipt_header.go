@@ -0,0 +1,79 @@
+package peac
+
+import (
+	"net/http"
+	"strings"
+)
+
+// AuthorizationScheme is the HTTP authentication scheme of the PEAC
+// challenge (WWW-Authenticate: PEAC ...). Clients answering it send the
+// receipt as "Authorization: PEAC <receipt>".
+const AuthorizationScheme = "PEAC"
+
+// HasPEACScheme reports whether an Authorization header value uses the PEAC
+// scheme, in any case.
+func HasPEACScheme(headerValue string) bool {
+	scheme, _, _ := strings.Cut(strings.TrimSpace(headerValue), " ")
+	return strings.EqualFold(scheme, AuthorizationScheme)
+}
+
+// ExtractReceipt returns the receipt token carried in an HTTP header value.
+// It accepts a bare compact JWS or one prefixed with the Bearer or PEAC
+// scheme, in any case, with surrounding whitespace, so the same value parses
+// whether it arrived in PEAC-Receipt or Authorization.
+//
+// An empty header fails with ErrIdentityMissing; a scheme without a token,
+// a scheme other than Bearer or PEAC, or a token containing whitespace fails
+// with ErrInvalidFormat.
+func ExtractReceipt(headerValue string) (string, error) {
+	value := strings.TrimSpace(headerValue)
+	if value == "" {
+		return "", NewPEACError(ErrIdentityMissing, "receipt header is empty")
+	}
+
+	token := value
+	if scheme, rest, ok := strings.Cut(value, " "); ok {
+		if !strings.EqualFold(scheme, "Bearer") && !strings.EqualFold(scheme, AuthorizationScheme) {
+			return "", NewPEACError(ErrInvalidFormat, "unsupported authorization scheme "+scheme)
+		}
+		token = strings.TrimSpace(rest)
+	} else if strings.EqualFold(value, "Bearer") || strings.EqualFold(value, AuthorizationScheme) {
+		token = ""
+	}
+
+	if token == "" {
+		return "", NewPEACError(ErrInvalidFormat, "receipt token is empty")
+	}
+	if strings.ContainsAny(token, " \t\r\n") {
+		return "", NewPEACError(ErrInvalidFormat, "receipt token must not contain whitespace")
+	}
+	return token, nil
+}
+
+// ReceiptFromRequest returns the receipt carried in the named request header
+// (default "PEAC-Receipt"), with the Bearer or PEAC scheme stripped by
+// ExtractReceipt.
+//
+// A missing or blank header fails with ErrIdentityMissing.
+func ReceiptFromRequest(r *http.Request, headerName string) (string, error) {
+	if headerName == "" {
+		headerName = "PEAC-Receipt"
+	}
+	header := r.Header.Get(headerName)
+	if strings.TrimSpace(header) == "" {
+		return "", NewPEACError(ErrIdentityMissing, headerName+" header is required")
+	}
+	return ExtractReceipt(header)
+}
+
+// VerifyRequest reads the receipt from the named request header with
+// ReceiptFromRequest and verifies it with VerifyLocal. The error reports
+// only a missing or malformed header; as with VerifyLocal, verification
+// failures are reported in the result.
+func VerifyRequest(r *http.Request, headerName string, opts VerifyLocalOptions) (*VerifyLocalResult, error) {
+	receipt, err := ReceiptFromRequest(r, headerName)
+	if err != nil {
+		return nil, err
+	}
+	return VerifyLocal(receipt, opts), nil
+}
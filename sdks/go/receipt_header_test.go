@@ -0,0 +1,121 @@
+package peac
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/peacprotocol/peac/sdks/go/jws"
+)
+
+func TestExtractReceipt(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		want     string
+		wantCode ErrorCode
+	}{
+		{"bare token", "a.b.c", "a.b.c", ""},
+		{"bearer", "Bearer a.b.c", "a.b.c", ""},
+		{"lowercase scheme", "bearer a.b.c", "a.b.c", ""},
+		{"uppercase scheme", "BEARER a.b.c", "a.b.c", ""},
+		{"extra whitespace", "  Bearer    a.b.c \t", "a.b.c", ""},
+		{"tab separator", "Bearer\ta.b.c", "", ErrInvalidFormat},
+		{"peac scheme", "PEAC a.b.c", "a.b.c", ""},
+		{"lowercase peac scheme", "peac a.b.c", "a.b.c", ""},
+		{"peac scheme only", "PEAC", "", ErrInvalidFormat},
+		{"empty", "", "", ErrIdentityMissing},
+		{"whitespace only", "   ", "", ErrIdentityMissing},
+		{"scheme only", "Bearer", "", ErrInvalidFormat},
+		{"scheme and spaces", "Bearer   ", "", ErrInvalidFormat},
+		{"other scheme", "Basic dXNlcjpwYXNz", "", ErrInvalidFormat},
+		{"token with space", "Bearer a.b c", "", ErrInvalidFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractReceipt(tt.header)
+			if tt.wantCode == "" {
+				if err != nil || got != tt.want {
+					t.Errorf("ExtractReceipt(%q) = %q, %v, want %q", tt.header, got, err, tt.want)
+				}
+				return
+			}
+			peacErr, ok := err.(*PEACError)
+			if !ok || peacErr.Code != tt.wantCode {
+				t.Errorf("ExtractReceipt(%q) error = %v, want %s", tt.header, err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestReceiptFromRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		headerName string
+		header     string
+		value      string
+		want       string
+		wantCode   ErrorCode
+		wantDetail string
+	}{
+		{"bare", "", "PEAC-Receipt", "a.b.c", "a.b.c", "", ""},
+		{"peac scheme", "Authorization", "Authorization", "PEAC a.b.c", "a.b.c", "", ""},
+		{"missing", "", "", "", "", ErrIdentityMissing, "PEAC-Receipt header is required"},
+		{"blank", "", "PEAC-Receipt", "  ", "", ErrIdentityMissing, ""},
+		{"custom header missing", "X-Receipt", "PEAC-Receipt", "a.b.c", "", ErrIdentityMissing, "X-Receipt header is required"},
+		{"other scheme", "Authorization", "Authorization", "Basic dXNlcjpwYXNz", "", ErrInvalidFormat, "unsupported authorization scheme"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				r.Header.Set(tt.header, tt.value)
+			}
+			got, err := ReceiptFromRequest(r, tt.headerName)
+			if tt.wantCode == "" {
+				if err != nil || got != tt.want {
+					t.Errorf("ReceiptFromRequest() = %q, %v, want %q", got, err, tt.want)
+				}
+				return
+			}
+			peacErr, ok := err.(*PEACError)
+			if !ok || peacErr.Code != tt.wantCode || !strings.Contains(peacErr.Message, tt.wantDetail) {
+				t.Errorf("ReceiptFromRequest() error = %v, want %s containing %q", err, tt.wantCode, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestVerifyRequest(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	issued, err := Issue(IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/test",
+		SigningKey: key,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := jws.GenerateSigningKey("key-1")
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("PEAC-Receipt", "Bearer "+issued.JWS)
+	result, err := VerifyRequest(r, "", VerifyLocalOptions{PublicKey: key.PublicKey(), Issuer: "https://example.com"})
+	if err != nil || !result.Valid {
+		t.Fatalf("VerifyRequest() = %+v, %v, want valid", result, err)
+	}
+
+	// Verification failures are in the result, not the error
+	result, err = VerifyRequest(r, "", VerifyLocalOptions{PublicKey: other.PublicKey()})
+	if err != nil || result.Valid || result.ErrorCode != "E_INVALID_SIGNATURE" {
+		t.Errorf("wrong key: result = %+v, err = %v, want E_INVALID_SIGNATURE result", result, err)
+	}
+
+	// Header problems are errors
+	_, err = VerifyRequest(httptest.NewRequest("GET", "/", nil), "", VerifyLocalOptions{PublicKey: key.PublicKey()})
+	if peacErr, ok := err.(*PEACError); !ok || peacErr.Code != ErrIdentityMissing {
+		t.Errorf("missing header: err = %v, want E_IDENTITY_MISSING", err)
+	}
+}
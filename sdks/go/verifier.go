@@ -0,0 +1,52 @@
+package peac
+
+import (
+	"context"
+	"time"
+
+	"github.com/peacprotocol/peac/sdks/go/jwks"
+)
+
+// Verifier verifies interaction records with options resolved once at
+// construction, so callers stop repeating the same issuer, key, and logger
+// settings on every call. It is safe for concurrent use.
+type Verifier struct {
+	opts     VerifyLocalOptions
+	registry *jwks.Registry
+}
+
+// NewVerifier creates a verifier for opts, applying defaults once. If
+// registry is non-nil, each record's key set is resolved from it by iss, as
+// VerifyLocalRegistry does, and opts.PublicKey and opts.KeySet are ignored;
+// otherwise they are used as VerifyLocal uses them.
+func NewVerifier(opts VerifyLocalOptions, registry *jwks.Registry) *Verifier {
+	if opts.MaxClockSkew == 0 {
+		opts.MaxClockSkew = 30 * time.Second
+	}
+	return &Verifier{opts: opts, registry: registry}
+}
+
+// Options returns the verifier's resolved options.
+func (v *Verifier) Options() VerifyLocalOptions {
+	return v.opts
+}
+
+// Verify verifies a single record. ctx bounds key-set resolution through the
+// registry; it is unused when keys are static.
+func (v *Verifier) Verify(ctx context.Context, receiptJWS string) *VerifyLocalResult {
+	if v.registry != nil {
+		return VerifyLocalRegistry(ctx, v.registry, receiptJWS, v.opts)
+	}
+	return VerifyLocal(receiptJWS, v.opts)
+}
+
+// VerifyMany verifies each record in jwsList and returns one result per
+// record, in input order. Failures are reported per item, as in
+// VerifyLocalMany.
+func (v *Verifier) VerifyMany(ctx context.Context, jwsList []string) []*VerifyLocalResult {
+	results := make([]*VerifyLocalResult, len(jwsList))
+	for i, receiptJWS := range jwsList {
+		results[i] = v.Verify(ctx, receiptJWS)
+	}
+	return results
+}
@@ -0,0 +1,74 @@
+package peac
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/peacprotocol/peac/sdks/go/jwks"
+	"github.com/peacprotocol/peac/sdks/go/jws"
+)
+
+func TestVerifier_KeySet(t *testing.T) {
+	k1, _ := jws.GenerateSigningKey("k1")
+	unknown, _ := jws.GenerateSigningKey("k2")
+
+	keySet := jwks.NewKeySet()
+	keySet.Add("k1", k1.PublicKey())
+
+	v := NewVerifier(VerifyLocalOptions{KeySet: keySet, Issuer: "https://example.com"}, nil)
+	if got := v.Options().MaxClockSkew; got != 30*time.Second {
+		t.Errorf("MaxClockSkew = %v, want 30s", got)
+	}
+
+	results := v.VerifyMany(context.Background(), issueBatch(t, k1, unknown))
+	for i, want := range []string{"", "E_KEY_NOT_FOUND"} {
+		if results[i].ErrorCode != want || results[i].Valid != (want == "") {
+			t.Errorf("results[%d] = valid=%v code=%s, want code %q", i, results[i].Valid, results[i].ErrorCode, want)
+		}
+	}
+}
+
+func TestVerifier_Registry(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("k1")
+	keySet := jwks.NewKeySet()
+	keySet.Add("k1", key.PublicKey())
+	registry := jwks.NewRegistry(nil)
+	registry.AddKeySet("https://example.com", keySet)
+
+	// Static keys are ignored in favor of the registry.
+	other, _ := jws.GenerateSigningKey("k1")
+	v := NewVerifier(VerifyLocalOptions{PublicKey: other.PublicKey()}, registry)
+
+	result := v.Verify(context.Background(), issueBatch(t, key)[0])
+	if !result.Valid {
+		t.Errorf("Verify() valid=false code=%s (%s)", result.ErrorCode, result.ErrorMessage)
+	}
+}
+
+// BenchmarkVerifier_CachedKey measures the hot path: a valid record whose
+// key is already in the verifier's key set.
+func BenchmarkVerifier_CachedKey(b *testing.B) {
+	key, _ := jws.GenerateSigningKey("k1")
+	receipt, err := IssueJWS(IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/test",
+		SigningKey: key,
+		Extensions: map[string]any{CommerceExtensionKey: map[string]any{"payment_rail": "stripe", "amount_minor": "100", "currency": "USD"}},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	keySet := jwks.NewKeySet()
+	keySet.Add("k1", key.PublicKey())
+	v := NewVerifier(VerifyLocalOptions{KeySet: keySet, Issuer: "https://example.com"}, nil)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if r := v.Verify(ctx, receipt); !r.Valid {
+			b.Fatal(r.ErrorMessage)
+		}
+	}
+}
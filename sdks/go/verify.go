@@ -2,7 +2,9 @@ package peac
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/peacprotocol/peac/sdks/go/jwks"
@@ -22,6 +24,10 @@ type VerifyOptions struct {
 	KeySet    *jwks.KeySet
 	JWKSCache *jwks.Cache
 	Context   context.Context
+
+	// MaxReceiptBytes caps the receipt length; larger receipts are rejected
+	// before decoding. Zero or negative disables the cap.
+	MaxReceiptBytes int
 }
 
 // VerifyResult contains the result of receipt verification.
@@ -40,7 +46,10 @@ type VerifyPerf struct {
 	JWKSFetchMs float64 `json:"jwks_fetch_ms,omitempty"`
 }
 
-// PEACReceiptClaims represents Wire 0.1 receipt claims.
+// PEACReceiptClaims represents Wire 0.1 receipt claims. Wire 0.1 named the
+// receipt identifier receipt_id; Interaction Records carry it as rid (and
+// jti with IssueOptions.StandardClaims), read via
+// InteractionRecordClaims.ReceiptID.
 //
 // Deprecated: Use InteractionRecordClaims for the current stable format.
 type PEACReceiptClaims struct {
@@ -60,14 +69,21 @@ type ErrorCode string
 //
 // Deprecated: Use sentinel errors for new code.
 const (
-	ErrInvalidSignature ErrorCode = "E_INVALID_SIGNATURE"
-	ErrInvalidFormat    ErrorCode = "E_INVALID_FORMAT"
-	ErrExpired          ErrorCode = "E_EXPIRED"
-	ErrNotYetValid      ErrorCode = "E_NOT_YET_VALID"
-	ErrInvalidIssuer    ErrorCode = "E_INVALID_ISSUER"
-	ErrInvalidAudience  ErrorCode = "E_INVALID_AUDIENCE"
-	ErrJWKSFetchFailed  ErrorCode = "E_JWKS_FETCH_FAILED"
-	ErrKeyNotFound      ErrorCode = "E_KEY_NOT_FOUND"
+	ErrInvalidSignature   ErrorCode = "E_INVALID_SIGNATURE"
+	ErrInvalidFormat      ErrorCode = "E_INVALID_FORMAT"
+	ErrExpired            ErrorCode = "E_EXPIRED"
+	ErrTooOld             ErrorCode = "E_TOO_OLD"
+	ErrIssuedBeforeCutoff ErrorCode = "E_ISSUED_BEFORE_CUTOFF"
+	ErrNotYetValid        ErrorCode = "E_NOT_YET_VALID"
+	ErrInvalidIssuer      ErrorCode = "E_INVALID_ISSUER"
+	ErrInvalidAudience    ErrorCode = "E_INVALID_AUDIENCE"
+	ErrJWKSFetchFailed    ErrorCode = "E_JWKS_FETCH_FAILED"
+	ErrKeyNotFound        ErrorCode = "E_KEY_NOT_FOUND"
+	ErrEnvMismatch        ErrorCode = "E_ENV_MISMATCH"
+	ErrAmountMismatch     ErrorCode = "E_AMOUNT_MISMATCH"
+	ErrCurrencyMismatch   ErrorCode = "E_CURRENCY_MISMATCH"
+	ErrPurposeMismatch    ErrorCode = "E_PURPOSE_MISMATCH"
+	ErrEvidenceTimeout    ErrorCode = "E_EVIDENCE_TIMEOUT"
 
 	ErrIdentityMissing              ErrorCode = "E_IDENTITY_MISSING"
 	ErrIdentityInvalidFormat        ErrorCode = "E_IDENTITY_INVALID_FORMAT"
@@ -121,10 +137,10 @@ func (e *PEACError) IsRetryable() bool {
 func (e *PEACError) HTTPStatus() int {
 	switch e.Code {
 	case ErrInvalidSignature, ErrInvalidFormat, ErrInvalidIssuer, ErrInvalidAudience,
-		ErrKeyNotFound, ErrIdentityInvalidFormat, ErrIdentityBindingMismatch,
-		ErrIdentityBindingFuture, ErrIdentityProofUnsupported:
+		ErrKeyNotFound, ErrEnvMismatch, ErrAmountMismatch, ErrCurrencyMismatch, ErrPurposeMismatch, ErrEvidenceTimeout, ErrIdentityInvalidFormat,
+		ErrIdentityBindingMismatch, ErrIdentityBindingFuture, ErrIdentityProofUnsupported:
 		return 400
-	case ErrExpired, ErrNotYetValid, ErrIdentityMissing, ErrIdentityExpired,
+	case ErrExpired, ErrTooOld, ErrIssuedBeforeCutoff, ErrNotYetValid, ErrIdentityMissing, ErrIdentityExpired,
 		ErrIdentityNotYetValid, ErrIdentitySigInvalid, ErrIdentityKeyUnknown,
 		ErrIdentityKeyExpired, ErrIdentityKeyRevoked, ErrIdentityBindingStale:
 		return 401
@@ -135,12 +151,49 @@ func (e *PEACError) HTTPStatus() int {
 	}
 }
 
+// StatusForError returns the HTTP status a gateway should send for err, so
+// verification and issuance failures translate consistently alongside
+// policy.StatusForDecision:
+//
+//   - nil: 200
+//   - *PEACError: HTTPStatus() (malformed input 400, auth failures 401,
+//     key directory unavailable 503)
+//   - *IssueError and issuance sentinel errors: 400, except signing and
+//     ID generation failures, which are 500
+//   - anything else: 500
+//
+// Wrapped errors are unwrapped with errors.As.
+func StatusForError(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	var peacErr *PEACError
+	if errors.As(err, &peacErr) {
+		return peacErr.HTTPStatus()
+	}
+	var issueErr *IssueError
+	if errors.As(err, &issueErr) {
+		switch issueErr.Code {
+		case ErrCodeSignFailed, ErrCodeIDGenFailed:
+			return http.StatusInternalServerError
+		default:
+			return http.StatusBadRequest
+		}
+	}
+	switch {
+	case errors.Is(err, ErrIssNotCanonical), errors.Is(err, ErrInvalidKind), errors.Is(err, ErrInvalidType),
+		errors.Is(err, ErrMissingRequired), errors.Is(err, ErrUnsupportedVersion):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
 // Verify verifies a receipt using JWKS resolution.
 //
 // Deprecated: This function supports Wire 0.1 only.
 // VerifyLocal() for the current stable format ships in v0.12.8 PR3.
 func Verify(receiptJWS string, opts VerifyOptions) (*VerifyResult, error) {
-	parsed, err := jws.Parse(receiptJWS)
+	parsed, err := jws.ParseWithLimit(receiptJWS, opts.MaxReceiptBytes)
 	if err != nil {
 		return nil, NewPEACError(ErrInvalidFormat, err.Error())
 	}
@@ -1,31 +1,261 @@
 package peac
 
 import (
+	"bytes"
+	"context"
 	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/peacprotocol/peac/sdks/go/evidence"
+	"github.com/peacprotocol/peac/sdks/go/jwks"
 	"github.com/peacprotocol/peac/sdks/go/jws"
 )
 
+// ValidateClaims runs the semantic checks VerifyLocal applies to decoded
+// claims (peac_version, kind, time bounds, issuer, env, and payment)
+// without parsing or verifying a signature. Use it when the record was
+// verified upstream, e.g. at a gateway, and only the claims were passed on. Policy binding and evidence limits are not checked
+// here.
+//
+// The returned error is a *PEACError carrying the same code and message
+// VerifyLocal would report, with the failing check under Details["check"].
+func ValidateClaims(claims *InteractionRecordClaims, opts VerifyLocalOptions) error {
+	if claims == nil {
+		return NewPEACError(ErrInvalidFormat, "claims are required").WithDetail("check", "claims")
+	}
+	if check, code, msg := validateClaims(claims, opts); code != "" {
+		return NewPEACError(ErrorCode(code), msg).WithDetail("check", check)
+	}
+	return nil
+}
+
+// validateClaims backs ValidateClaims and VerifyLocal. It returns the failing
+// check, code, and message, or an empty code when the claims are acceptable.
+func validateClaims(claims *InteractionRecordClaims, opts VerifyLocalOptions) (check, code, msg string) {
+	// Validate peac_version
+	if claims.PeacVersion != PeacVersion {
+		return "peac_version", "E_UNSUPPORTED_WIRE_VERSION", fmt.Sprintf("expected peac_version %s, got %s", PeacVersion, claims.PeacVersion)
+	}
+
+	// rid and jti name the same identifier when both are present
+	if claims.Rid != "" && claims.Jti != "" && claims.Rid != claims.Jti {
+		return "claims", "E_INVALID_FORMAT", fmt.Sprintf("rid %q and jti %q disagree", claims.Rid, claims.Jti)
+	}
+
+	// Validate kind
+	if !ValidKinds[claims.Kind] {
+		return "kind", "E_CONSTRAINT_VIOLATION", fmt.Sprintf("invalid kind %q", claims.Kind)
+	}
+
+	if len(claims.PurposeDeclared) > MaxPurposeDeclaredLength {
+		return "purpose_declared", "E_INVALID_FORMAT", fmt.Sprintf("purpose_declared exceeds %d bytes", MaxPurposeDeclaredLength)
+	}
+
+	// Apply default clock skew
+	maxSkew := opts.MaxClockSkew
+	if maxSkew == 0 {
+		maxSkew = 30 * time.Second
+	}
+	now := time.Now()
+
+	// Check iat (not in future), first against the hard ceiling
+	iat := time.Unix(claims.Iat, 0)
+	if opts.MaxFutureSkew > 0 && iat.After(now.Add(opts.MaxFutureSkew)) {
+		return "iat_ceiling", "E_NOT_YET_VALID", fmt.Sprintf("iat is implausibly far in the future (%s ahead, ceiling %s)", iat.Sub(now).Truncate(time.Second), opts.MaxFutureSkew)
+	}
+	if iat.After(now.Add(maxSkew)) {
+		return "iat", "E_NOT_YET_VALID", "iat is in the future"
+	}
+
+	// Check age (if bounded locally)
+	if window := maxAgeWithLeeway(opts); opts.MaxAge > 0 && now.Sub(iat)-maxSkew > window {
+		return "max_age", "E_TOO_OLD", fmt.Sprintf("interaction record is older than max age %s", window)
+	}
+
+	// Check issuance cutoff
+	if !opts.MinIssuedAt.IsZero() && iat.Before(opts.MinIssuedAt) {
+		return "min_iat", "E_ISSUED_BEFORE_CUTOFF", fmt.Sprintf("interaction record was issued before cutoff %s", opts.MinIssuedAt.UTC().Format(time.RFC3339))
+	}
+
+	// Check exp (if present)
+	if claims.Exp > 0 {
+		exp := time.Unix(claims.Exp, 0)
+		if exp.Before(now.Add(-maxSkew)) {
+			return "exp", "E_EXPIRED", "interaction record has expired"
+		}
+	} else if opts.RequireExp {
+		return "exp", "E_CONSTRAINT_VIOLATION", "exp is required but not present"
+	}
+
+	// Check issuer match
+	if msg := checkIssuer(claims.Iss, opts); msg != "" {
+		return "issuer", "E_INVALID_ISSUER", msg
+	}
+
+	// Check payment environment
+	if opts.ExpectedEnv != "" {
+		commerce, err := claims.Commerce()
+		if err != nil {
+			return "env", "E_INVALID_FORMAT", err.Error()
+		}
+		if commerce != nil && commerce.Env != opts.ExpectedEnv {
+			return "env", "E_ENV_MISMATCH", fmt.Sprintf("expected env %q, got %q", opts.ExpectedEnv, commerce.Env)
+		}
+	}
+
+	// Check payment amount and currency
+	if opts.ExpectedCurrency != "" || opts.MinAmount > 0 {
+		if check, code, msg := checkPayment(claims, opts); code != "" {
+			return check, code, msg
+		}
+	}
+
+	// Check the commerce extension is well formed
+	if opts.CheckPaymentConsistency {
+		if msg := checkPaymentConsistency(claims); msg != "" {
+			return "payment", "E_INVALID_FORMAT", msg
+		}
+	}
+
+	return "", "", ""
+}
+
+// maxAgeWithLeeway returns opts.MaxAge extended by MaxAgeLeewayFraction. A
+// fraction that is not a positive finite number adds no leeway, and a
+// window past the largest Duration saturates.
+func maxAgeWithLeeway(opts VerifyLocalOptions) time.Duration {
+	f := opts.MaxAgeLeewayFraction
+	if !(f > 0) || math.IsInf(f, 1) {
+		return opts.MaxAge
+	}
+	extra := float64(opts.MaxAge) * f
+	if extra >= float64(math.MaxInt64-opts.MaxAge) {
+		return math.MaxInt64
+	}
+	return opts.MaxAge + time.Duration(extra)
+}
+
+// checkPayment compares the commerce extension against opts.ExpectedCurrency
+// and opts.MinAmount. It returns the failing check, code, and message, or an
+// empty code when the payment is acceptable.
+func checkPayment(claims *InteractionRecordClaims, opts VerifyLocalOptions) (check, code, msg string) {
+	commerce, err := claims.Commerce()
+	if err != nil {
+		return "payment", "E_INVALID_FORMAT", err.Error()
+	}
+	if opts.ExpectedCurrency != "" {
+		if commerce == nil {
+			return "currency", "E_CURRENCY_MISMATCH", "no commerce extension present"
+		}
+		if commerce.Currency != opts.ExpectedCurrency {
+			return "currency", "E_CURRENCY_MISMATCH", fmt.Sprintf("expected currency %q, got %q", opts.ExpectedCurrency, commerce.Currency)
+		}
+	}
+	if opts.MinAmount > 0 {
+		if commerce == nil {
+			return "amount", "E_AMOUNT_MISMATCH", "no commerce extension present"
+		}
+		amount, err := strconv.ParseInt(commerce.AmountMinor, 10, 64)
+		if err != nil {
+			return "amount", "E_INVALID_FORMAT", fmt.Sprintf("invalid amount_minor %q", commerce.AmountMinor)
+		}
+		if amount < opts.MinAmount {
+			return "amount", "E_AMOUNT_MISMATCH", fmt.Sprintf("amount %d is below minimum %d", amount, opts.MinAmount)
+		}
+	}
+	return "", "", ""
+}
+
+// checkPaymentConsistency backs VerifyLocalOptions.CheckPaymentConsistency.
+// It returns a message naming the offending commerce field, or "".
+func checkPaymentConsistency(claims *InteractionRecordClaims) string {
+	commerce, err := claims.Commerce()
+	if err != nil {
+		return err.Error()
+	}
+	if commerce == nil {
+		return "commerce extension is required"
+	}
+	switch {
+	case commerce.PaymentRail == "":
+		return "commerce.payment_rail is required"
+	case commerce.AmountMinor == "":
+		return "commerce.amount_minor is required"
+	case commerce.Currency == "":
+		return "commerce.currency is required"
+	}
+	if !isMinorAmount(commerce.AmountMinor) {
+		return fmt.Sprintf("commerce.amount_minor must be a base-10 integer string, got %q", commerce.AmountMinor)
+	}
+	return ""
+}
+
+// isMinorAmount reports whether s matches the commerce amount_minor
+// grammar, -?[0-9]+, with no size limit.
+func isMinorAmount(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeClaims unmarshals payload into claims. In strict mode unknown fields
+// are rejected; the decoder error names the offending field. Otherwise they
+// are kept in claims.Extra.
+func decodeClaims(payload []byte, claims *InteractionRecordClaims, strict bool) error {
+	if !strict {
+		if err := json.Unmarshal(payload, claims); err != nil {
+			return err
+		}
+		extra, err := extraClaims(payload)
+		if err != nil {
+			return err
+		}
+		claims.Extra = extra
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(claims); err != nil {
+		return err
+	}
+	if dec.More() {
+		return fmt.Errorf("unexpected data after claims object")
+	}
+	return nil
+}
+
 // decodeCompactHeaderAndPayload splits a compact JWS and base64url-decodes the
 // protected header and payload segments, so the I-JSON gate can run on the raw
 // bytes BEFORE any JSON parsing (matching the TypeScript verify path).
 func decodeCompactHeaderAndPayload(receiptJWS string) ([]byte, []byte, error) {
-	parts := strings.Split(receiptJWS, ".")
-	if len(parts) != 3 {
+	if strings.Count(receiptJWS, ".") != 2 {
 		return nil, nil, fmt.Errorf("JWS compact serialization must have three parts")
 	}
-	headerRaw, err := base64.RawURLEncoding.DecodeString(parts[0])
+	header, rest, _ := strings.Cut(receiptJWS, ".")
+	payload, _, _ := strings.Cut(rest, ".")
+	headerRaw, err := base64.RawURLEncoding.DecodeString(header)
 	if err != nil {
 		return nil, nil, fmt.Errorf("JWS protected header: invalid base64url")
 	}
-	payloadRaw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	payloadRaw, err := base64.RawURLEncoding.DecodeString(payload)
 	if err != nil {
 		return nil, nil, fmt.Errorf("JWS payload: invalid base64url")
 	}
@@ -34,22 +264,145 @@ func decodeCompactHeaderAndPayload(receiptJWS string) ([]byte, []byte, error) {
 
 // VerifyLocalOptions contains options for local interaction record verification.
 type VerifyLocalOptions struct {
-	// PublicKey is the Ed25519 public key (32 bytes, required).
+	// PublicKey is the Ed25519 public key (32 bytes). Required unless KeySet
+	// is set.
 	PublicKey ed25519.PublicKey
 
+	// KeySet resolves the verification key by the header kid when PublicKey
+	// is not set. A kid missing from the set fails with E_KEY_NOT_FOUND.
+	KeySet *jwks.KeySet
+
 	// Issuer is the expected issuer URI (optional; if set, iss must match).
 	Issuer string
 
+	// TrustedIssuers is an allowlist of issuer URIs (optional), for gateways
+	// fronting many publishers. When non-empty, iss must be one of them or
+	// verification fails with E_INVALID_ISSUER. Issuer takes precedence:
+	// when both are set, only Issuer is checked.
+	TrustedIssuers []string
+
 	// MaxClockSkew is the tolerance for clock differences (default: 30 seconds).
 	MaxClockSkew time.Duration
 
+	// MaxFutureSkew is a hard ceiling on how far in the future iat may be
+	// (optional). A record dated beyond it is implausible rather than
+	// drifted: it still fails with E_NOT_YET_VALID, but with an
+	// "implausibly far in the future" message and the iat_ceiling check in
+	// the verification log, so monitoring can tell a clock-skew attack or
+	// issuer bug from ordinary drift past MaxClockSkew. It applies even when
+	// MaxClockSkew is larger. Zero disables the ceiling.
+	MaxFutureSkew time.Duration
+
 	// RequireExp requires the exp claim to be present.
 	RequireExp bool
 
+	// MaxAge bounds the record's age from iat, regardless of exp (optional).
+	// A record older than MaxAge (plus MaxClockSkew) fails with E_TOO_OLD,
+	// distinct from E_EXPIRED so monitoring can separate a local freshness
+	// window from an exp breach. Zero disables the check.
+	MaxAge time.Duration
+
+	// MaxAgeLeewayFraction extends MaxAge by that fraction of itself
+	// (optional), e.g. 0.1 accepts records up to 10% past MaxAge, for
+	// propagation delays that scale with the window. It composes with
+	// MaxClockSkew, which is still added on top, and affects only the
+	// MaxAge check. Zero, negative, NaN, or infinite means no leeway.
+	MaxAgeLeewayFraction float64
+
+	// MinIssuedAt invalidates every record issued before the given instant
+	// (optional), e.g. after a key-compromise cutover, without touching the
+	// JWKS. A record whose iat is earlier fails with E_ISSUED_BEFORE_CUTOFF.
+	// MaxClockSkew is not applied: the cutoff is exact. Zero disables the
+	// check.
+	MinIssuedAt time.Time
+
+	// AllowedKeyIDs pins the acceptable kid values (optional). When non-empty,
+	// a record signed under any other kid is rejected with E_KEY_NOT_FOUND
+	// before the signature is checked, even if the key is otherwise known.
+	// Use it to retire a compromised key without waiting for JWKS propagation.
+	AllowedKeyIDs []string
+
+	// AllowedAlgorithms pins the acceptable JWS alg values (optional). When
+	// non-empty, a record whose alg is not listed is rejected with
+	// E_INVALID_FORMAT before key resolution. The header check already
+	// limits records to EdDSA; this lets a deployment state its allowlist
+	// explicitly and fail closed if that ever widens.
+	AllowedAlgorithms []string
+
+	// DebugKeyIDs lists the kids available in KeySet in the E_KEY_NOT_FOUND
+	// message, to diagnose a kid missing from a fetched JWKS. It reveals the
+	// key inventory to whoever sees the error, so leave it off in production.
+	DebugKeyIDs bool
+
+	// ExpectedEnv is the payment environment this verifier accepts, EnvLive
+	// or EnvTest (optional). Records whose commerce extension declares a
+	// different env, or none, fail with E_ENV_MISMATCH. Records without a
+	// commerce extension are unaffected.
+	ExpectedEnv string
+
+	// ExpectedCurrency is the currency the commerce extension must declare
+	// (optional). A different currency fails with E_CURRENCY_MISMATCH.
+	ExpectedCurrency string
+
+	// MinAmount is the minimum commerce amount_minor accepted (optional).
+	// A smaller amount fails with E_AMOUNT_MISMATCH. Compare it together
+	// with ExpectedCurrency; minor units are not comparable across
+	// currencies.
+	//
+	// When ExpectedCurrency or MinAmount is set, a record without a commerce
+	// extension fails with the corresponding code, since it evidences no
+	// payment at all.
+	MinAmount int64
+
+	// CheckPaymentConsistency requires the commerce extension to be present
+	// and internally consistent: payment_rail, amount_minor, and currency
+	// set, and amount_minor a signed base-10 integer string (negative for
+	// refunds and chargebacks, any precision). A violation fails with
+	// E_INVALID_FORMAT naming the offending field.
+	CheckPaymentConsistency bool
+
+	// StrictClaims rejects payloads carrying claims InteractionRecordClaims
+	// does not define, failing with E_INVALID_FORMAT and naming the field.
+	// It is the recommended setting: the Wire 0.2 envelope is closed, so
+	// such records are not valid Wire 0.2. It is off by default only to
+	// keep existing callers working; lenient parsing collects the unknown
+	// claims in Claims.Extra. Extension contents under ext are not
+	// affected.
+	StrictClaims bool
+
 	// PolicyBytes is the local policy document for binding check (optional).
 	// When provided, the policy digest is computed via JCS + SHA-256 and
 	// compared with claims.Peac.Digest.
 	PolicyBytes []byte
+
+	// ValidateEvidence runs the record's extensions (ext) through
+	// evidence.ValidateValue with EvidenceLimits, extending the DoS limits
+	// Issue applies to the verify side so downstream code never walks an
+	// oversized or deeply nested ext. A violation fails with
+	// E_INVALID_FORMAT on the evidence check.
+	ValidateEvidence bool
+
+	// EvidenceLimits bounds validated evidence (optional). Unset fields fall
+	// back to DefaultEvidenceLimits().
+	EvidenceLimits evidence.Limits
+
+	// Timeout bounds the whole verification (optional), including key-set
+	// resolution in VerifyLocalRegistry and Verifier, so one knob holds the
+	// caller's SLA regardless of JWKS latency. It derives a child of the
+	// caller's context, so the tighter deadline wins. Running out while
+	// resolving keys fails with E_VERIFY_KEY_FETCH_TIMEOUT; the remaining
+	// time also caps EvidenceTimeout. Zero means no overall deadline.
+	Timeout time.Duration
+
+	// EvidenceTimeout caps the time spent validating evidence (optional).
+	// Exceeding it fails with E_EVIDENCE_TIMEOUT on the evidence check, the
+	// code Issue reports for the same condition. Zero means no deadline.
+	EvidenceTimeout time.Duration
+
+	// Logger receives one structured log line per verification (optional).
+	// Failures log at Warn with issuer, kid, error code, and the failing
+	// check; successes log at Debug with timing. Nil disables logging.
+	Logger *slog.Logger
 }
 
 // VerifyLocalResult contains the result of local interaction record verification.
@@ -63,6 +416,11 @@ type VerifyLocalResult struct {
 	// Kid is the key ID from the JWS header.
 	Kid string
 
+	// KeyStatus is the peac:status of the KeySet key the signature verified
+	// under, such as jwks.StatusDeprecated during a rotation. It is empty
+	// when the record was checked against PublicKey.
+	KeyStatus string
+
 	// Algorithm is always "EdDSA" for Ed25519.
 	Algorithm string
 
@@ -81,6 +439,9 @@ type VerifyLocalResult struct {
 	// Error details (populated only when Valid is false).
 	ErrorCode    string
 	ErrorMessage string
+
+	// failedCheck names the verification step that rejected the record.
+	failedCheck string
 }
 
 // VerificationWarning represents a non-fatal verification warning.
@@ -95,15 +456,47 @@ type VerificationWarning struct {
 // Enforces the current stable Interaction Record format (interaction-record+jwt)
 // at the protocol layer. The underlying jws/ package remains typ-agnostic.
 func VerifyLocal(receiptJWS string, opts VerifyLocalOptions) *VerifyLocalResult {
-	result := &VerifyLocalResult{
+	opts.EvidenceTimeout = capTimeout(opts.EvidenceTimeout, opts.Timeout)
+	if opts.Logger == nil {
+		return verifyLocal(receiptJWS, opts)
+	}
+	start := time.Now()
+	result := verifyLocal(receiptJWS, opts)
+	logVerifyLocal(opts.Logger, receiptJWS, result, time.Since(start))
+	return result
+}
+
+// capTimeout returns the tighter of two optional (zero means none) timeouts.
+func capTimeout(timeout, limit time.Duration) time.Duration {
+	if limit > 0 && (timeout <= 0 || limit < timeout) {
+		return limit
+	}
+	return timeout
+}
+
+// fail records a verification failure on the result. check names the
+// verification step that rejected the record (used for structured logging).
+func (r *VerifyLocalResult) fail(check, code, message string) *VerifyLocalResult {
+	r.failedCheck = check
+	r.ErrorCode = code
+	r.ErrorMessage = message
+	return r
+}
+
+// newVerifyLocalResult returns an unverified result for receiptJWS with
+// receipt_ref computed.
+func newVerifyLocalResult(receiptJWS string) *VerifyLocalResult {
+	h := sha256.Sum256([]byte(receiptJWS))
+	return &VerifyLocalResult{
 		Algorithm:     "EdDSA",
 		WireVersion:   PeacVersion,
 		PolicyBinding: PolicyBindingUnavailable,
+		ReceiptRef:    "sha256:" + hex.EncodeToString(h[:]),
 	}
+}
 
-	// Compute receipt_ref
-	h := sha256.Sum256([]byte(receiptJWS))
-	result.ReceiptRef = "sha256:" + hex.EncodeToString(h[:])
+func verifyLocal(receiptJWS string, opts VerifyLocalOptions) *VerifyLocalResult {
+	result := newVerifyLocalResult(receiptJWS)
 
 	// I-JSON (RFC 7493) gate on the raw protected-header and payload bytes BEFORE
 	// any JSON parsing (matching the TypeScript verify path, which gates before
@@ -111,121 +504,103 @@ func VerifyLocal(receiptJWS string, opts VerifyLocalOptions) *VerifyLocalResult
 	// before header parsing and before signature verification.
 	headerRaw, payloadRaw, err := decodeCompactHeaderAndPayload(receiptJWS)
 	if err != nil {
-		result.ErrorCode = "E_INVALID_FORMAT"
-		result.ErrorMessage = fmt.Sprintf("invalid JWS: %v", err)
-		return result
+		return result.fail("format", "E_INVALID_FORMAT", fmt.Sprintf("invalid JWS: %v", err))
 	}
 	for _, raw := range [][]byte{headerRaw, payloadRaw} {
 		if err := assertIJSON(raw); err != nil {
+			code := "E_INVALID_FORMAT"
 			if ie, ok := err.(*ijsonError); ok {
-				result.ErrorCode = ie.Code
-			} else {
-				result.ErrorCode = "E_INVALID_FORMAT"
+				code = ie.Code
 			}
-			result.ErrorMessage = err.Error()
-			return result
+			return result.fail("ijson", code, err.Error())
 		}
 	}
 
 	// Parse JWS
 	parsed, err := jws.Parse(receiptJWS)
 	if err != nil {
-		result.ErrorCode = "E_INVALID_FORMAT"
-		result.ErrorMessage = fmt.Sprintf("invalid JWS: %v", err)
-		return result
+		return result.fail("format", "E_INVALID_FORMAT", fmt.Sprintf("invalid JWS: %v", err))
+	}
+
+	// Algorithm pinning
+	if len(opts.AllowedAlgorithms) > 0 && !slices.Contains(opts.AllowedAlgorithms, parsed.Header.Algorithm) {
+		return result.fail("alg", "E_INVALID_FORMAT", fmt.Sprintf("algorithm %q is not allowed", parsed.Header.Algorithm))
 	}
 
 	// Low-level header validation (typ-agnostic)
 	if err := jws.ValidateHeader(parsed.Header); err != nil {
-		result.ErrorCode = "E_INVALID_FORMAT"
-		result.ErrorMessage = fmt.Sprintf("invalid header: %v", err)
-		return result
+		return result.fail("header", "E_INVALID_FORMAT", fmt.Sprintf("invalid header: %v", err))
+	}
+	if len(parsed.Signature) == 0 {
+		return result.fail("signature", "E_INVALID_SIGNATURE", jws.ErrEmptySignature.Error())
 	}
 
 	result.Kid = parsed.Header.KeyID
 
 	// Protocol-layer format enforcement: require interaction-record+jwt
 	if parsed.Header.Type != InteractionRecordTyp {
-		result.ErrorCode = "E_UNSUPPORTED_WIRE_VERSION"
-		result.ErrorMessage = fmt.Sprintf("expected typ %s, got %s", InteractionRecordTyp, parsed.Header.Type)
-		return result
+		return result.fail("typ", "E_UNSUPPORTED_WIRE_VERSION", fmt.Sprintf("expected typ %s, got %s", InteractionRecordTyp, parsed.Header.Type))
 	}
 
 	// JOSE hardening: reject unsafe header fields
 	if err := checkJOSEHardening(parsed.HeaderRaw); err != nil {
-		result.ErrorCode = "E_INVALID_FORMAT"
-		result.ErrorMessage = err.Error()
-		return result
+		return result.fail("jose_hardening", "E_INVALID_FORMAT", err.Error())
 	}
 
-	// Verify Ed25519 signature
-	if len(opts.PublicKey) != ed25519.PublicKeySize {
-		result.ErrorCode = "E_INVALID_FORMAT"
-		result.ErrorMessage = fmt.Sprintf("invalid public key size: expected %d, got %d", ed25519.PublicKeySize, len(opts.PublicKey))
-		return result
-	}
-	if err := jws.VerifyJWS(parsed, opts.PublicKey); err != nil {
-		result.ErrorCode = "E_INVALID_SIGNATURE"
-		result.ErrorMessage = "Ed25519 signature verification failed"
-		return result
+	// Key ID pinning
+	if len(opts.AllowedKeyIDs) > 0 && !slices.Contains(opts.AllowedKeyIDs, result.Kid) {
+		return result.fail("kid", "E_KEY_NOT_FOUND", fmt.Sprintf("kid %q is not in the allowed key IDs", result.Kid))
 	}
 
-	// Unmarshal claims
-	var claims InteractionRecordClaims
-	if err := json.Unmarshal(parsed.Payload, &claims); err != nil {
-		result.ErrorCode = "E_INVALID_FORMAT"
-		result.ErrorMessage = fmt.Sprintf("failed to parse claims: %v", err)
-		return result
+	// Resolve the verification key candidates
+	candidates := []ed25519.PublicKey{opts.PublicKey}
+	if opts.PublicKey == nil && opts.KeySet != nil {
+		candidates = opts.KeySet.Candidates(result.Kid)
+		if len(candidates) == 0 {
+			msg := fmt.Sprintf("kid %q not found in key set", result.Kid)
+			if opts.DebugKeyIDs {
+				msg += fmt.Sprintf(" (available: %s)", strings.Join(opts.KeySet.KeyIDs(), ", "))
+			}
+			return result.fail("public_key", "E_KEY_NOT_FOUND", msg)
+		}
 	}
 
-	// Validate peac_version
-	if claims.PeacVersion != PeacVersion {
-		result.ErrorCode = "E_UNSUPPORTED_WIRE_VERSION"
-		result.ErrorMessage = fmt.Sprintf("expected peac_version %s, got %s", PeacVersion, claims.PeacVersion)
-		return result
+	// Verify Ed25519 signature; any candidate sharing the kid may sign
+	for _, publicKey := range candidates {
+		if len(publicKey) != ed25519.PublicKeySize {
+			return result.fail("public_key", "E_INVALID_FORMAT", fmt.Sprintf("invalid public key size: expected %d, got %d", ed25519.PublicKeySize, len(publicKey)))
+		}
 	}
-
-	// Validate kind
-	if !ValidKinds[claims.Kind] {
-		result.ErrorCode = "E_CONSTRAINT_VIOLATION"
-		result.ErrorMessage = fmt.Sprintf("invalid kind %q", claims.Kind)
-		return result
+	signer := slices.IndexFunc(candidates, func(publicKey ed25519.PublicKey) bool {
+		return jws.VerifyJWS(parsed, publicKey) == nil
+	})
+	if signer < 0 {
+		return result.fail("signature", "E_INVALID_SIGNATURE", "Ed25519 signature verification failed")
 	}
-
-	// Apply default clock skew
-	maxSkew := opts.MaxClockSkew
-	if maxSkew == 0 {
-		maxSkew = 30 * time.Second
+	if opts.PublicKey == nil && opts.KeySet != nil {
+		result.KeyStatus, _ = opts.KeySet.KeyStatus(result.Kid, candidates[signer])
 	}
-	now := time.Now()
 
-	// Check iat (not in future)
-	iat := time.Unix(claims.Iat, 0)
-	if iat.After(now.Add(maxSkew)) {
-		result.ErrorCode = "E_NOT_YET_VALID"
-		result.ErrorMessage = "iat is in the future"
-		return result
+	// Unmarshal claims
+	var claims InteractionRecordClaims
+	if err := decodeClaims(parsed.Payload, &claims, opts.StrictClaims); err != nil {
+		return result.fail("claims", "E_INVALID_FORMAT", fmt.Sprintf("failed to parse claims: %v", err))
 	}
 
-	// Check exp (if present)
-	if claims.Exp > 0 {
-		exp := time.Unix(claims.Exp, 0)
-		if exp.Before(now.Add(-maxSkew)) {
-			result.ErrorCode = "E_EXPIRED"
-			result.ErrorMessage = "interaction record has expired"
-			return result
+	// Bound the ext as sent
+	if opts.ValidateEvidence && claims.Ext != nil {
+		limits := opts.EvidenceLimits.MergeWith(DefaultEvidenceLimits())
+		if err := validateEvidence(claims.Ext, limits, opts.EvidenceTimeout); err != nil {
+			code := "E_INVALID_FORMAT"
+			if errors.Is(err, context.DeadlineExceeded) {
+				code = "E_EVIDENCE_TIMEOUT"
+			}
+			return result.fail("evidence", code, fmt.Sprintf("extension validation failed: %v", err))
 		}
-	} else if opts.RequireExp {
-		result.ErrorCode = "E_CONSTRAINT_VIOLATION"
-		result.ErrorMessage = "exp is required but not present"
-		return result
 	}
 
-	// Check issuer match
-	if opts.Issuer != "" && claims.Iss != opts.Issuer {
-		result.ErrorCode = "E_INVALID_ISSUER"
-		result.ErrorMessage = fmt.Sprintf("expected issuer %s, got %s", opts.Issuer, claims.Iss)
-		return result
+	if check, code, msg := validateClaims(&claims, opts); code != "" {
+		return result.fail(check, code, msg)
 	}
 
 	// Policy binding
@@ -234,9 +609,7 @@ func VerifyLocal(receiptJWS string, opts VerifyLocalOptions) *VerifyLocalResult
 		if err == nil {
 			result.PolicyBinding = CheckPolicyBinding(claims.Peac.Digest, localDigest)
 			if result.PolicyBinding == PolicyBindingFailed {
-				result.ErrorCode = "E_POLICY_BINDING_FAILED"
-				result.ErrorMessage = "policy digest mismatch"
-				return result
+				return result.fail("policy_binding", "E_POLICY_BINDING_FAILED", "policy digest mismatch")
 			}
 		}
 	} else if opts.PolicyBytes != nil && (claims.Peac == nil || claims.Peac.Digest == "") {
@@ -248,6 +621,63 @@ func VerifyLocal(receiptJWS string, opts VerifyLocalOptions) *VerifyLocalResult
 	return result
 }
 
+// logVerifyLocal emits one structured log line for a VerifyLocal outcome:
+// Warn on failure (with the failing check) and Debug on success.
+func logVerifyLocal(logger *slog.Logger, receiptJWS string, result *VerifyLocalResult, elapsed time.Duration) {
+	durationMs := float64(elapsed.Microseconds()) / 1000
+	if result.Valid {
+		logger.Debug("peac verify succeeded",
+			"peac.iss", result.Claims.Iss,
+			"peac.kid", result.Kid,
+			"peac.receipt.ref", result.ReceiptRef,
+			"peac.verify.duration_ms", durationMs,
+		)
+		return
+	}
+	logger.Warn("peac verify failed",
+		"peac.iss", unverifiedIssuer(receiptJWS),
+		"peac.kid", result.Kid,
+		"peac.error.code", result.ErrorCode,
+		"peac.verify.check", result.failedCheck,
+		"peac.error.message", result.ErrorMessage,
+		"peac.receipt.ref", result.ReceiptRef,
+		"peac.verify.duration_ms", durationMs,
+	)
+}
+
+// checkIssuer returns why iss fails opts.Issuer or opts.TrustedIssuers, or
+// "" if it is acceptable.
+func checkIssuer(iss string, opts VerifyLocalOptions) string {
+	switch {
+	case opts.Issuer != "":
+		if iss != opts.Issuer {
+			return fmt.Sprintf("expected issuer %s, got %s", opts.Issuer, iss)
+		}
+	case len(opts.TrustedIssuers) > 0:
+		if !slices.Contains(opts.TrustedIssuers, iss) {
+			return fmt.Sprintf("issuer %s is not trusted (allowed: %s)", iss, strings.Join(opts.TrustedIssuers, ", "))
+		}
+	}
+	return ""
+}
+
+// unverifiedIssuer best-effort extracts the iss claim from a record before
+// or after a failed verification. The value is untrusted: it is used only for
+// logging and to select a key set in VerifyLocalRegistry.
+func unverifiedIssuer(receiptJWS string) string {
+	_, payloadRaw, err := decodeCompactHeaderAndPayload(receiptJWS)
+	if err != nil {
+		return ""
+	}
+	var claims struct {
+		Iss string `json:"iss"`
+	}
+	if err := json.Unmarshal(payloadRaw, &claims); err != nil {
+		return ""
+	}
+	return claims.Iss
+}
+
 // checkJOSEHardening rejects unsafe JOSE header fields per Wire 0.2 spec.
 func checkJOSEHardening(headerRaw []byte) error {
 	var raw map[string]json.RawMessage
@@ -0,0 +1,48 @@
+package peac
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peacprotocol/peac/sdks/go/jwks"
+)
+
+// VerifyLocalMany verifies each record in jwsList with the same options and
+// returns one result per record, in input order. Failures are reported per
+// item on the result, exactly as VerifyLocal reports them.
+//
+// Use it with opts.KeySet to verify a batch from one issuer without
+// re-resolving keys per record. A JWS string that appears more than once is
+// verified once, and every position it occupies shares the same result.
+func VerifyLocalMany(jwsList []string, opts VerifyLocalOptions) []*VerifyLocalResult {
+	results := make([]*VerifyLocalResult, len(jwsList))
+	seen := make(map[string]*VerifyLocalResult, len(jwsList))
+	for i, receiptJWS := range jwsList {
+		result, ok := seen[receiptJWS]
+		if !ok {
+			result = VerifyLocal(receiptJWS, opts)
+			seen[receiptJWS] = result
+		}
+		results[i] = result
+	}
+	return results
+}
+
+// VerifyLocalManyJWKS resolves the key set at jwksURL once, through cache,
+// and verifies every record in jwsList against it. An error is returned only
+// if the key set cannot be resolved; per-record failures are on the results.
+//
+// opts.KeySet is used as-is when already set, and no fetch is made.
+func VerifyLocalManyJWKS(ctx context.Context, cache *jwks.Cache, jwksURL string, jwsList []string, opts VerifyLocalOptions) ([]*VerifyLocalResult, error) {
+	if opts.KeySet == nil && opts.PublicKey == nil {
+		if cache == nil {
+			return nil, fmt.Errorf("jwks cache is required to resolve %s", jwksURL)
+		}
+		keySet, err := cache.Get(ctx, jwksURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve key set: %w", err)
+		}
+		opts.KeySet = keySet
+	}
+	return VerifyLocalMany(jwsList, opts), nil
+}
@@ -0,0 +1,151 @@
+package peac
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/peacprotocol/peac/sdks/go/jwks"
+	"github.com/peacprotocol/peac/sdks/go/jws"
+)
+
+func issueBatch(t *testing.T, keys ...*jws.SigningKey) []string {
+	t.Helper()
+	out := make([]string, len(keys))
+	for i, key := range keys {
+		s, err := IssueJWS(IssueOptions{
+			Iss:        "https://example.com",
+			Kind:       KindEvidence,
+			Type:       "org.peacprotocol/test",
+			SigningKey: key,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[i] = s
+	}
+	return out
+}
+
+func TestVerifyLocalMany_KeySet(t *testing.T) {
+	k1, _ := jws.GenerateSigningKey("k1")
+	k2, _ := jws.GenerateSigningKey("k2")
+	unknown, _ := jws.GenerateSigningKey("k3")
+
+	keySet := jwks.NewKeySet()
+	keySet.Add("k1", k1.PublicKey())
+	keySet.Add("k2", k2.PublicKey())
+
+	results := VerifyLocalMany(issueBatch(t, k1, k2, unknown), VerifyLocalOptions{KeySet: keySet})
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	for i, want := range []string{"", "", "E_KEY_NOT_FOUND"} {
+		if results[i].ErrorCode != want || results[i].Valid != (want == "") {
+			t.Errorf("results[%d] = valid=%v code=%s, want code %q", i, results[i].Valid, results[i].ErrorCode, want)
+		}
+	}
+}
+
+func TestVerifyLocalMany_Duplicates(t *testing.T) {
+	k1, _ := jws.GenerateSigningKey("k1")
+	other, _ := jws.GenerateSigningKey("k2")
+	batch := issueBatch(t, k1, other)
+	jwsList := []string{batch[0], batch[1], batch[0], "not-a-jws", batch[1], batch[0]}
+
+	// The logger emits one line per VerifyLocal call.
+	var logs bytes.Buffer
+	results := VerifyLocalMany(jwsList, VerifyLocalOptions{
+		PublicKey: k1.PublicKey(),
+		Logger:    slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	})
+	if len(results) != len(jwsList) {
+		t.Fatalf("got %d results, want %d", len(results), len(jwsList))
+	}
+	for i, want := range []string{"", "E_INVALID_SIGNATURE", "", "E_INVALID_FORMAT", "E_INVALID_SIGNATURE", ""} {
+		if results[i].ErrorCode != want || results[i].Valid != (want == "") {
+			t.Errorf("results[%d] = valid=%v code=%s, want code %q", i, results[i].Valid, results[i].ErrorCode, want)
+		}
+	}
+	if n := bytes.Count(logs.Bytes(), []byte("\n")); n != 3 {
+		t.Errorf("verified %d records, want 3 unique", n)
+	}
+}
+
+func TestVerifyLocalManyJWKS_FetchesOnce(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("k1")
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(jwks.JWKS{Keys: []jwks.JWK{{
+			KeyType: "OKP",
+			Curve:   "Ed25519",
+			KeyID:   "k1",
+			X:       jws.Encode(key.PublicKey()),
+		}}})
+	}))
+	defer srv.Close()
+
+	batch := issueBatch(t, key, key, key, key)
+	results, err := VerifyLocalManyJWKS(context.Background(), jwks.NewCache(jwks.DefaultCacheOptions()), srv.URL, batch, VerifyLocalOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range results {
+		if !r.Valid {
+			t.Errorf("results[%d]: %s: %s", i, r.ErrorCode, r.ErrorMessage)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", n)
+	}
+}
+
+func TestVerifyLocalManyJWKS_ResolutionError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	_, err := VerifyLocalManyJWKS(context.Background(), jwks.NewCache(jwks.DefaultCacheOptions()), srv.URL, []string{"x"}, VerifyLocalOptions{})
+	if err == nil {
+		t.Error("expected key set resolution error")
+	}
+}
+
+// BenchmarkVerifyLocalMany_HalfDuplicates verifies a 100-record batch in
+// which every record appears twice. "loop" calls VerifyLocal per record;
+// "many" verifies each unique record once, halving the Ed25519 checks.
+func BenchmarkVerifyLocalMany_HalfDuplicates(b *testing.B) {
+	key, _ := jws.GenerateSigningKey("k1")
+	jwsList := make([]string, 0, 100)
+	for range 50 {
+		s, err := IssueJWS(IssueOptions{Iss: "https://example.com", Kind: KindEvidence, Type: "org.peacprotocol/test", SigningKey: key})
+		if err != nil {
+			b.Fatal(err)
+		}
+		jwsList = append(jwsList, s, s)
+	}
+	opts := VerifyLocalOptions{PublicKey: key.PublicKey()}
+
+	b.Run("loop", func(b *testing.B) {
+		for b.Loop() {
+			for _, s := range jwsList {
+				VerifyLocal(s, opts)
+			}
+		}
+		b.ReportMetric(float64(len(jwsList)), "verifies/op")
+	})
+	b.Run("many", func(b *testing.B) {
+		for b.Loop() {
+			VerifyLocalMany(jwsList, opts)
+		}
+		b.ReportMetric(float64(len(jwsList)/2), "verifies/op")
+	})
+}
@@ -0,0 +1,74 @@
+package peac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/peacprotocol/peac/sdks/go/jwks"
+)
+
+// VerifyLocalRegistry verifies a record whose key set is resolved from
+// registry by the record's iss claim. The iss is read before verification
+// only to select the key set; because the signature is then checked against
+// that issuer's keys, a verified record always carries the iss it was
+// resolved under.
+//
+// An issuer outside opts.Issuer or opts.TrustedIssuers fails with
+// E_INVALID_ISSUER before any key set is resolved, so only allowed issuers
+// drive JWKS discovery. An unregistered issuer fails with E_KEY_NOT_FOUND,
+// an unreachable JWKS with E_JWKS_FETCH_FAILED, and resolution past ctx's
+// deadline or opts.Timeout with E_VERIFY_KEY_FETCH_TIMEOUT; otherwise the
+// result is exactly VerifyLocal's. opts.PublicKey and opts.KeySet are
+// ignored.
+func VerifyLocalRegistry(ctx context.Context, registry *jwks.Registry, receiptJWS string, opts VerifyLocalOptions) *VerifyLocalResult {
+	issuer := unverifiedIssuer(receiptJWS)
+	if issuer == "" {
+		// Malformed or iss-less: let VerifyLocal report the format error,
+		// with an empty key set so nothing can verify.
+		opts.PublicKey = nil
+		opts.KeySet = jwks.NewKeySet()
+		return VerifyLocal(receiptJWS, opts)
+	}
+	if msg := checkIssuer(issuer, opts); msg != "" {
+		result := newVerifyLocalResult(receiptJWS).fail("issuer", "E_INVALID_ISSUER", msg)
+		if opts.Logger != nil {
+			logVerifyLocal(opts.Logger, receiptJWS, result, 0)
+		}
+		return result
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	keySet, err := registry.KeySet(ctx, issuer)
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		code := "E_JWKS_FETCH_FAILED"
+		switch {
+		case errors.Is(err, jwks.ErrUnknownIssuer):
+			code = "E_KEY_NOT_FOUND"
+		case errors.Is(err, context.DeadlineExceeded):
+			code = "E_VERIFY_KEY_FETCH_TIMEOUT"
+		}
+		result := newVerifyLocalResult(receiptJWS).fail("issuer", code, fmt.Sprintf("failed to resolve key set: %v", err))
+		if opts.Logger != nil {
+			logVerifyLocal(opts.Logger, receiptJWS, result, 0)
+		}
+		return result
+	}
+
+	// The rest of the budget bounds verification itself.
+	if deadline, ok := ctx.Deadline(); ok {
+		opts.Timeout = capTimeout(opts.Timeout, time.Until(deadline))
+	}
+	opts.PublicKey = nil
+	opts.KeySet = keySet
+	return VerifyLocal(receiptJWS, opts)
+}
@@ -0,0 +1,119 @@
+package peac
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/peacprotocol/peac/sdks/go/jwks"
+	"github.com/peacprotocol/peac/sdks/go/jws"
+)
+
+func TestVerifyLocalRegistry(t *testing.T) {
+	keyA, _ := jws.GenerateSigningKey("a-1")
+	keyB, _ := jws.GenerateSigningKey("b-1")
+	issue := func(iss string, key *jws.SigningKey) string {
+		t.Helper()
+		issued, err := Issue(IssueOptions{Iss: iss, Kind: KindEvidence, Type: "org.peacprotocol/test", SigningKey: key})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return issued.JWS
+	}
+
+	registry := jwks.NewRegistry(nil)
+	setA := jwks.NewKeySet()
+	setA.Add("a-1", keyA.PublicKey())
+	registry.AddKeySet("https://a.example", setA)
+	setB := jwks.NewKeySet()
+	setB.Add("b-1", keyB.PublicKey())
+	registry.AddKeySet("https://b.example", setB)
+
+	tests := []struct {
+		name     string
+		jws      string
+		wantCode string
+	}{
+		{"tenant a", issue("https://a.example", keyA), ""},
+		{"tenant b", issue("https://b.example", keyB), ""},
+		{"claims a but signed by b", issue("https://a.example", keyB), "E_KEY_NOT_FOUND"},
+		{"unregistered issuer", issue("https://c.example", keyA), "E_KEY_NOT_FOUND"},
+		{"malformed", "not-a-jws", "E_INVALID_FORMAT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := VerifyLocalRegistry(context.Background(), registry, tt.jws, VerifyLocalOptions{})
+			if result.ErrorCode != tt.wantCode || result.Valid != (tt.wantCode == "") {
+				t.Errorf("valid=%v code=%s (%s), want code %q", result.Valid, result.ErrorCode, result.ErrorMessage, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestVerifyLocalRegistry_TrustedIssuers(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("k1")
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+	registry := jwks.NewRegistry(nil)
+	registry.Add("https://untrusted.example", srv.URL)
+
+	issued, err := Issue(IssueOptions{Iss: "https://untrusted.example", Kind: KindEvidence, Type: "org.peacprotocol/test", SigningKey: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := VerifyLocalRegistry(context.Background(), registry, issued.JWS, VerifyLocalOptions{TrustedIssuers: []string{"https://trusted.example"}})
+	if result.Valid || result.ErrorCode != "E_INVALID_ISSUER" {
+		t.Errorf("valid=%v code=%s, want E_INVALID_ISSUER", result.Valid, result.ErrorCode)
+	}
+	if n := fetches.Load(); n != 0 {
+		t.Errorf("fetched JWKS %d times for an untrusted issuer, want 0", n)
+	}
+}
+
+func TestVerifyLocalRegistry_Timeout(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("k1")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	registry := jwks.NewRegistry(nil)
+	registry.Add("https://example.com", srv.URL)
+	receipt := issueBatch(t, key)[0]
+
+	tests := []struct {
+		name    string
+		ctx     func() (context.Context, context.CancelFunc)
+		timeout time.Duration
+	}{
+		{"option", func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) }, 50 * time.Millisecond},
+		{"caller deadline tighter", func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), 50*time.Millisecond)
+		}, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			start := time.Now()
+			result := VerifyLocalRegistry(ctx, registry, receipt, VerifyLocalOptions{Timeout: tt.timeout})
+			if result.ErrorCode != "E_VERIFY_KEY_FETCH_TIMEOUT" {
+				t.Errorf("code = %s (%s), want E_VERIFY_KEY_FETCH_TIMEOUT", result.ErrorCode, result.ErrorMessage)
+			}
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("took %v, want bounded by the tighter deadline", elapsed)
+			}
+		})
+	}
+}
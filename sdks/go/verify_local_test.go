@@ -1,9 +1,18 @@
 package peac
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/peacprotocol/peac/sdks/go/evidence"
+	"github.com/peacprotocol/peac/sdks/go/jwks"
 	"github.com/peacprotocol/peac/sdks/go/jws"
 )
 
@@ -108,6 +117,41 @@ func TestVerifyLocal_IssuerMismatch(t *testing.T) {
 	}
 }
 
+func TestVerifyLocal_TrustedIssuers(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	issued, _ := Issue(IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/test",
+		SigningKey: key,
+	})
+
+	tests := []struct {
+		name     string
+		issuer   string
+		trusted  []string
+		wantCode string
+	}{
+		{"allowed", "", []string{"https://a.example", "https://example.com"}, ""},
+		{"not allowed", "", []string{"https://a.example", "https://b.example"}, "E_INVALID_ISSUER"},
+		{"issuer takes precedence", "https://example.com", []string{"https://a.example"}, ""},
+		{"issuer mismatch ignores allowlist", "https://a.example", []string{"https://example.com"}, "E_INVALID_ISSUER"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := VerifyLocal(issued.JWS, VerifyLocalOptions{PublicKey: key.PublicKey(), Issuer: tt.issuer, TrustedIssuers: tt.trusted})
+			if result.ErrorCode != tt.wantCode || result.Valid != (tt.wantCode == "") {
+				t.Errorf("valid=%v code=%s, want code %q", result.Valid, result.ErrorCode, tt.wantCode)
+			}
+		})
+	}
+
+	result := VerifyLocal(issued.JWS, VerifyLocalOptions{PublicKey: key.PublicKey(), TrustedIssuers: []string{"https://a.example", "https://b.example"}})
+	if !strings.Contains(result.ErrorMessage, "https://a.example, https://b.example") {
+		t.Errorf("error %q does not list the allowed issuers", result.ErrorMessage)
+	}
+}
+
 func TestVerifyLocal_PolicyBindingVerified(t *testing.T) {
 	key, _ := jws.GenerateSigningKey("key-1")
 	policy := []byte(`{"rule": "allow"}`)
@@ -218,3 +262,733 @@ func TestVerifyLocal_ReceiptRefMatchesSHA256(t *testing.T) {
 		t.Errorf("receipt_ref length = %d, want 71", len(result.ReceiptRef))
 	}
 }
+
+func TestVerifyLocal_LoggerFailure(t *testing.T) {
+	key1, _ := jws.GenerateSigningKey("key-1")
+	key2, _ := jws.GenerateSigningKey("key-2")
+	issued, _ := Issue(IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/test",
+		SigningKey: key1,
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	result := VerifyLocal(issued.JWS, VerifyLocalOptions{
+		PublicKey: key2.PublicKey(),
+		Logger:    logger,
+	})
+	if result.Valid {
+		t.Fatal("expected invalid for wrong key")
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected a single JSON log line, got %q: %v", buf.String(), err)
+	}
+	want := map[string]string{
+		"level":             "WARN",
+		"peac.iss":          "https://example.com",
+		"peac.kid":          "key-1",
+		"peac.error.code":   "E_INVALID_SIGNATURE",
+		"peac.verify.check": "signature",
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("log %s = %v, want %s", k, entry[k], v)
+		}
+	}
+}
+
+func TestVerifyLocal_LoggerSuccessIsDebug(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	issued, _ := Issue(IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/test",
+		SigningKey: key,
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil)) // Info level: debug suppressed
+
+	result := VerifyLocal(issued.JWS, VerifyLocalOptions{
+		PublicKey: key.PublicKey(),
+		Logger:    logger,
+	})
+	if !result.Valid {
+		t.Fatalf("expected valid, got %s", result.ErrorCode)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output at Info level on success, got %q", buf.String())
+	}
+}
+
+func TestVerifyLocal_AllowedKeyIDs(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-2024")
+	issued, _ := Issue(IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/test",
+		SigningKey: key,
+	})
+
+	result := VerifyLocal(issued.JWS, VerifyLocalOptions{
+		PublicKey:     key.PublicKey(),
+		AllowedKeyIDs: []string{"key-2025"},
+	})
+	if result.Valid {
+		t.Fatal("expected invalid for kid outside the allowed set")
+	}
+	if result.ErrorCode != "E_KEY_NOT_FOUND" {
+		t.Errorf("code = %s, want E_KEY_NOT_FOUND", result.ErrorCode)
+	}
+
+	result = VerifyLocal(issued.JWS, VerifyLocalOptions{
+		PublicKey:     key.PublicKey(),
+		AllowedKeyIDs: []string{"key-2025", "key-2024"},
+	})
+	if !result.Valid {
+		t.Fatalf("expected valid, got error: %s: %s", result.ErrorCode, result.ErrorMessage)
+	}
+}
+
+func TestVerifyLocal_AllowedAlgorithms(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	issued, _ := Issue(IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/test",
+		SigningKey: key,
+	})
+	parts := strings.Split(issued.JWS, ".")
+	es256 := jws.Encode([]byte(`{"alg":"ES256","typ":"`+InteractionRecordTyp+`","kid":"key-1"}`)) + "." + parts[1] + "." + parts[2]
+
+	tests := []struct {
+		name     string
+		receipt  string
+		allowed  []string
+		wantCode string
+		wantMsg  string
+	}{
+		{"unset accepts EdDSA", issued.JWS, nil, "", ""},
+		{"listed EdDSA", issued.JWS, []string{"EdDSA"}, "", ""},
+		{"EdDSA not listed", issued.JWS, []string{"ES256"}, "E_INVALID_FORMAT", `algorithm "EdDSA" is not allowed`},
+		{"ES256 not listed", es256, []string{"EdDSA"}, "E_INVALID_FORMAT", `algorithm "ES256" is not allowed`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := VerifyLocal(tt.receipt, VerifyLocalOptions{PublicKey: key.PublicKey(), AllowedAlgorithms: tt.allowed})
+			if r.ErrorCode != tt.wantCode || r.Valid != (tt.wantCode == "") || !strings.Contains(r.ErrorMessage, tt.wantMsg) {
+				t.Errorf("valid=%v code=%s msg=%q, want %q containing %q", r.Valid, r.ErrorCode, r.ErrorMessage, tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestVerifyLocal_KeySetDuplicateKid(t *testing.T) {
+	oldKey, _ := jws.GenerateSigningKey("rotating")
+	newKey, _ := jws.GenerateSigningKey("rotating")
+	keySet := jwks.NewKeySet()
+	keySet.AddWithStatus("rotating", oldKey.PublicKey(), jwks.StatusDeprecated)
+	keySet.Add("rotating", newKey.PublicKey())
+
+	for _, tt := range []struct {
+		key    *jws.SigningKey
+		status string
+	}{{oldKey, jwks.StatusDeprecated}, {newKey, jwks.StatusActive}} {
+		issued, err := IssueJWS(IssueOptions{Iss: "https://example.com", Kind: KindEvidence, Type: "org.peacprotocol/test", SigningKey: tt.key})
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := VerifyLocal(issued, VerifyLocalOptions{KeySet: keySet})
+		if !result.Valid {
+			t.Errorf("expected valid under either candidate, got %s: %s", result.ErrorCode, result.ErrorMessage)
+		}
+		if result.KeyStatus != tt.status {
+			t.Errorf("KeyStatus = %q, want %q for the key that verified", result.KeyStatus, tt.status)
+		}
+	}
+
+	stranger, _ := jws.GenerateSigningKey("rotating")
+	issued, _ := IssueJWS(IssueOptions{Iss: "https://example.com", Kind: KindEvidence, Type: "org.peacprotocol/test", SigningKey: stranger})
+	if result := VerifyLocal(issued, VerifyLocalOptions{KeySet: keySet}); result.ErrorCode != "E_INVALID_SIGNATURE" {
+		t.Errorf("code = %s, want E_INVALID_SIGNATURE", result.ErrorCode)
+	}
+}
+
+func TestVerifyLocal_DebugKeyIDs(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-2024")
+	issued, _ := Issue(IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/test",
+		SigningKey: key,
+	})
+	other, _ := jws.GenerateSigningKey("key-2025")
+	keySet := jwks.NewKeySet()
+	keySet.Add("key-2025", other.PublicKey())
+
+	result := VerifyLocal(issued.JWS, VerifyLocalOptions{KeySet: keySet})
+	if result.ErrorCode != "E_KEY_NOT_FOUND" || strings.Contains(result.ErrorMessage, "key-2025") {
+		t.Errorf("default: code=%s message=%q, want E_KEY_NOT_FOUND without inventory", result.ErrorCode, result.ErrorMessage)
+	}
+
+	result = VerifyLocal(issued.JWS, VerifyLocalOptions{KeySet: keySet, DebugKeyIDs: true})
+	if result.ErrorCode != "E_KEY_NOT_FOUND" || !strings.Contains(result.ErrorMessage, "available: key-2025") {
+		t.Errorf("debug: code=%s message=%q, want available kids listed", result.ErrorCode, result.ErrorMessage)
+	}
+}
+
+func TestVerifyLocal_ValidateEvidence(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	issued, err := Issue(IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/test",
+		SigningKey: key,
+		Extensions: map[string]any{"com.example/rail": map[string]any{"a": map[string]any{"b": map[string]any{"c": "d"}}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     VerifyLocalOptions
+		wantCode string
+	}{
+		{"off by default", VerifyLocalOptions{EvidenceLimits: evidence.Limits{MaxDepth: 2}}, ""},
+		{"within limits", VerifyLocalOptions{ValidateEvidence: true}, ""},
+		{"too deep", VerifyLocalOptions{ValidateEvidence: true, EvidenceLimits: evidence.Limits{MaxDepth: 2}}, "E_INVALID_FORMAT"},
+		{"too many nodes", VerifyLocalOptions{ValidateEvidence: true, EvidenceLimits: evidence.Limits{MaxTotalNodes: 2}}, "E_INVALID_FORMAT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.PublicKey = key.PublicKey()
+			result := VerifyLocal(issued.JWS, tt.opts)
+			if result.ErrorCode != tt.wantCode || result.Valid != (tt.wantCode == "") {
+				t.Errorf("valid=%v code=%s (%s), want code %q", result.Valid, result.ErrorCode, result.ErrorMessage, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestVerifyLocal_EvidenceTimeout(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	items := make([]any, 5000)
+	for i := range items {
+		items[i] = "x"
+	}
+	issued, err := Issue(IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/test",
+		SigningKey: key,
+		Extensions: map[string]any{"com.example/bulk": items},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := VerifyLocalOptions{PublicKey: key.PublicKey(), ValidateEvidence: true, EvidenceTimeout: time.Nanosecond}
+	result := VerifyLocal(issued.JWS, opts)
+	if result.Valid || result.ErrorCode != ErrCodeEvidenceTimeout {
+		t.Fatalf("valid=%v code=%s, want %s", result.Valid, result.ErrorCode, ErrEvidenceTimeout)
+	}
+
+	opts.EvidenceTimeout = time.Minute
+	if result := VerifyLocal(issued.JWS, opts); !result.Valid {
+		t.Errorf("VerifyLocal() with generous timeout failed: %s", result.ErrorMessage)
+	}
+}
+
+func TestVerifyLocal_MaxAge(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	issued, _ := Issue(IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/test",
+		SigningKey: key,
+		Exp:        time.Now().Add(24 * time.Hour).Unix(), // generous exp
+		Clock:      FixedClock{Time: time.Now().Add(-2 * time.Hour)},
+	})
+
+	result := VerifyLocal(issued.JWS, VerifyLocalOptions{
+		PublicKey: key.PublicKey(),
+		MaxAge:    time.Hour,
+	})
+	if result.Valid {
+		t.Fatal("expected invalid for record older than MaxAge")
+	}
+	if result.ErrorCode != "E_TOO_OLD" {
+		t.Errorf("code = %s, want E_TOO_OLD", result.ErrorCode)
+	}
+
+	result = VerifyLocal(issued.JWS, VerifyLocalOptions{
+		PublicKey: key.PublicKey(),
+		MaxAge:    3 * time.Hour,
+	})
+	if !result.Valid {
+		t.Errorf("expected valid within MaxAge, got %s: %s", result.ErrorCode, result.ErrorMessage)
+	}
+}
+
+func TestVerifyLocal_MaxAgeLeewayFraction(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	issued, _ := Issue(IssueOptions{
+		Iss:        "https://example.com",
+		Kind:       KindEvidence,
+		Type:       "org.peacprotocol/test",
+		SigningKey: key,
+		Clock:      FixedClock{Time: time.Now().Add(-65 * time.Minute)},
+	})
+
+	tests := []struct {
+		name       string
+		fraction   float64
+		wantCode   string
+		wantWindow string
+	}{
+		{"no leeway", 0, "E_TOO_OLD", "1h0m0s"},
+		{"negative ignored", -0.5, "E_TOO_OLD", "1h0m0s"},
+		{"NaN ignored", math.NaN(), "E_TOO_OLD", "1h0m0s"},
+		{"infinity ignored", math.Inf(1), "E_TOO_OLD", "1h0m0s"},
+		{"5% too small", 0.05, "E_TOO_OLD", "1h3m0s"},
+		{"10% covers it", 0.1, "", ""},
+		{"huge fraction saturates", 1e300, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := VerifyLocal(issued.JWS, VerifyLocalOptions{
+				PublicKey:            key.PublicKey(),
+				MaxAge:               time.Hour,
+				MaxAgeLeewayFraction: tt.fraction,
+			})
+			if result.ErrorCode != tt.wantCode || result.Valid != (tt.wantCode == "") {
+				t.Errorf("valid=%v code=%s, want code %q", result.Valid, result.ErrorCode, tt.wantCode)
+			}
+			if tt.wantWindow != "" && !strings.HasSuffix(result.ErrorMessage, "max age "+tt.wantWindow) {
+				t.Errorf("message = %q, want the effective window %s", result.ErrorMessage, tt.wantWindow)
+			}
+		})
+	}
+}
+
+func TestVerifyLocal_MinIssuedAt(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	cutover := time.Now().Add(-time.Hour)
+	issueAt := func(at time.Time) string {
+		t.Helper()
+		issued, err := Issue(IssueOptions{
+			Iss:        "https://example.com",
+			Kind:       KindEvidence,
+			Type:       "org.peacprotocol/test",
+			SigningKey: key,
+			Clock:      FixedClock{Time: at},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return issued.JWS
+	}
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		wantCode string
+	}{
+		{"before cutover", cutover.Add(-time.Minute), "E_ISSUED_BEFORE_CUTOFF"},
+		{"at cutover", cutover.Truncate(time.Second).Add(time.Second), ""},
+		{"after cutover", cutover.Add(time.Minute), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := VerifyLocal(issueAt(tt.at), VerifyLocalOptions{PublicKey: key.PublicKey(), MinIssuedAt: cutover})
+			if result.ErrorCode != tt.wantCode || result.Valid != (tt.wantCode == "") {
+				t.Errorf("valid=%v code=%s, want code %q", result.Valid, result.ErrorCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestVerifyLocal_MaxFutureSkew(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	issueAt := func(at time.Time) string {
+		t.Helper()
+		issued, err := Issue(IssueOptions{
+			Iss:        "https://example.com",
+			Kind:       KindEvidence,
+			Type:       "org.peacprotocol/test",
+			SigningKey: key,
+			Clock:      FixedClock{Time: at},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return issued.JWS
+	}
+
+	tests := []struct {
+		name     string
+		ahead    time.Duration
+		opts     VerifyLocalOptions
+		wantCode string
+		wantMsg  string
+	}{
+		{"within skew", 10 * time.Second, VerifyLocalOptions{MaxFutureSkew: time.Hour}, "", ""},
+		{"drift past skew", 10 * time.Minute, VerifyLocalOptions{MaxFutureSkew: time.Hour}, "E_NOT_YET_VALID", "iat is in the future"},
+		{"implausibly future", 48 * time.Hour, VerifyLocalOptions{MaxFutureSkew: time.Hour}, "E_NOT_YET_VALID", "implausibly far in the future"},
+		{"ceiling below skew", 10 * time.Minute, VerifyLocalOptions{MaxClockSkew: time.Hour, MaxFutureSkew: time.Minute}, "E_NOT_YET_VALID", "implausibly far in the future"},
+		{"no ceiling", 48 * time.Hour, VerifyLocalOptions{}, "E_NOT_YET_VALID", "iat is in the future"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.PublicKey = key.PublicKey()
+			result := VerifyLocal(issueAt(time.Now().Add(tt.ahead)), opts)
+			if result.ErrorCode != tt.wantCode || result.Valid != (tt.wantCode == "") {
+				t.Fatalf("valid=%v code=%s, want code %q", result.Valid, result.ErrorCode, tt.wantCode)
+			}
+			if !strings.Contains(result.ErrorMessage, tt.wantMsg) {
+				t.Errorf("message = %q, want it to contain %q", result.ErrorMessage, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestVerifyLocal_ExpectedEnv(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	issueWith := func(ext map[string]any) string {
+		t.Helper()
+		issued, err := Issue(IssueOptions{
+			Iss:        "https://example.com",
+			Kind:       KindEvidence,
+			Type:       "org.peacprotocol/payment",
+			SigningKey: key,
+			Extensions: ext,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return issued.JWS
+	}
+	commerce := func(env string) map[string]any {
+		m := map[string]any{"payment_rail": "stripe", "amount_minor": "100", "currency": "USD"}
+		if env != "" {
+			m["env"] = env
+		}
+		return map[string]any{CommerceExtensionKey: m}
+	}
+
+	tests := []struct {
+		name     string
+		jws      string
+		wantCode string
+	}{
+		{"live matches", issueWith(commerce(EnvLive)), ""},
+		{"test rejected by live verifier", issueWith(commerce(EnvTest)), "E_ENV_MISMATCH"},
+		{"undeclared env rejected", issueWith(commerce("")), "E_ENV_MISMATCH"},
+		{"no commerce extension", issueWith(nil), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := VerifyLocal(tt.jws, VerifyLocalOptions{PublicKey: key.PublicKey(), ExpectedEnv: EnvLive})
+			if result.ErrorCode != tt.wantCode || result.Valid != (tt.wantCode == "") {
+				t.Errorf("valid=%v code=%s, want code %q", result.Valid, result.ErrorCode, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestVerifyLocal_StrictClaims(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	payload := []byte(`{"iss":"https://example.com","iat":` + strconv.FormatInt(time.Now().Unix(), 10) +
+		`,"rid":"r1","kind":"evidence","type":"org.peacprotocol/test","peac_version":"` + PeacVersion +
+		`","ext":{"com.example/anything":{"x":1}},"exipres":123}`)
+	signed, err := key.SignWithType(payload, InteractionRecordTyp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lenient := VerifyLocal(signed, VerifyLocalOptions{PublicKey: key.PublicKey()})
+	if !lenient.Valid {
+		t.Fatalf("lenient verification failed: %s", lenient.ErrorMessage)
+	}
+
+	strict := VerifyLocal(signed, VerifyLocalOptions{PublicKey: key.PublicKey(), StrictClaims: true})
+	if strict.Valid || strict.ErrorCode != "E_INVALID_FORMAT" {
+		t.Fatalf("valid=%v code=%s, want E_INVALID_FORMAT", strict.Valid, strict.ErrorCode)
+	}
+	if !strings.Contains(strict.ErrorMessage, `"exipres"`) {
+		t.Errorf("error %q does not name the unknown field", strict.ErrorMessage)
+	}
+
+	// Issued records use only known claims and pass strict decoding.
+	issued, err := Issue(IssueOptions{Iss: "https://example.com", Kind: KindEvidence, Type: "org.peacprotocol/test", PurposeDeclared: "train", SigningKey: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := VerifyLocal(issued.JWS, VerifyLocalOptions{PublicKey: key.PublicKey(), StrictClaims: true})
+	if !r.Valid {
+		t.Fatalf("strict verification of issued record failed: %s", r.ErrorMessage)
+	}
+	if r.Claims.PurposeDeclared != "train" {
+		t.Errorf("PurposeDeclared = %q, want train", r.Claims.PurposeDeclared)
+	}
+}
+
+func TestVerifyLocal_JtiReceiptID(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	sign := func(ids string) string {
+		payload := []byte(`{"iss":"https://example.com","iat":` + strconv.FormatInt(time.Now().Unix(), 10) +
+			`,` + ids + `,"kind":"evidence","type":"org.peacprotocol/test","peac_version":"` + PeacVersion + `"}`)
+		signed, err := key.SignWithType(payload, InteractionRecordTyp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	jtiOnly := VerifyLocal(sign(`"jti":"j1"`), VerifyLocalOptions{PublicKey: key.PublicKey()})
+	if !jtiOnly.Valid || jtiOnly.Claims.ReceiptID() != "j1" {
+		t.Errorf("jti only: valid=%v ReceiptID()=%q, want j1", jtiOnly.Valid, jtiOnly.Claims.ReceiptID())
+	}
+
+	mismatch := VerifyLocal(sign(`"rid":"r1","jti":"j1"`), VerifyLocalOptions{PublicKey: key.PublicKey()})
+	if mismatch.Valid || mismatch.ErrorCode != "E_INVALID_FORMAT" {
+		t.Errorf("rid/jti mismatch: valid=%v code=%s, want E_INVALID_FORMAT", mismatch.Valid, mismatch.ErrorCode)
+	}
+}
+
+func TestVerifyLocal_ExtraClaims(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	payload := []byte(`{"iss":"https://example.com","iat":` + strconv.FormatInt(time.Now().Unix(), 10) +
+		`,"rid":"r1","kind":"evidence","type":"org.peacprotocol/test","peac_version":"` + PeacVersion +
+		`","com.example/tenant":{"id":"t-1"}}`)
+	signed, err := key.SignWithType(payload, InteractionRecordTyp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := VerifyLocal(signed, VerifyLocalOptions{PublicKey: key.PublicKey()})
+	if !result.Valid {
+		t.Fatalf("verification failed: %s", result.ErrorMessage)
+	}
+	if len(result.Claims.Extra) != 1 {
+		t.Fatalf("Extra = %v, want only the custom claim", result.Claims.Extra)
+	}
+	var tenant struct {
+		ID string `json:"id"`
+	}
+	if err := result.Claims.GetExtra("com.example/tenant", &tenant); err != nil || tenant.ID != "t-1" {
+		t.Errorf("GetExtra() = %+v, %v, want id t-1", tenant, err)
+	}
+	if err := result.Claims.GetExtra("com.example/missing", &tenant); !errors.Is(err, ErrClaimNotFound) {
+		t.Errorf("GetExtra(missing) error = %v, want ErrClaimNotFound", err)
+	}
+
+	// Records with only known claims have no Extra.
+	issued, err := Issue(IssueOptions{Iss: "https://example.com", Kind: KindEvidence, Type: "org.peacprotocol/test", SigningKey: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := VerifyLocal(issued.JWS, VerifyLocalOptions{PublicKey: key.PublicKey()}); r.Claims.Extra != nil {
+		t.Errorf("Extra = %v, want nil", r.Claims.Extra)
+	}
+}
+
+func TestVerifyLocal_PaymentAmountAndCurrency(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	issueWith := func(ext map[string]any) string {
+		t.Helper()
+		issued, err := Issue(IssueOptions{
+			Iss:        "https://example.com",
+			Kind:       KindEvidence,
+			Type:       "org.peacprotocol/payment",
+			SigningKey: key,
+			Extensions: ext,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return issued.JWS
+	}
+	commerce := func(amount, currency string) map[string]any {
+		return map[string]any{CommerceExtensionKey: map[string]any{
+			"payment_rail": "stripe", "amount_minor": amount, "currency": currency,
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		jws      string
+		wantCode string
+	}{
+		{"meets minimum", issueWith(commerce("500", "USD")), ""},
+		{"exceeds minimum", issueWith(commerce("1000", "USD")), ""},
+		{"under-charged", issueWith(commerce("499", "USD")), "E_AMOUNT_MISMATCH"},
+		{"wrong currency", issueWith(commerce("500", "EUR")), "E_CURRENCY_MISMATCH"},
+		{"malformed amount", issueWith(commerce("5.00", "USD")), "E_INVALID_FORMAT"},
+		{"no commerce extension", issueWith(nil), "E_CURRENCY_MISMATCH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := VerifyLocal(tt.jws, VerifyLocalOptions{
+				PublicKey:        key.PublicKey(),
+				ExpectedCurrency: "USD",
+				MinAmount:        500,
+			})
+			if result.ErrorCode != tt.wantCode || result.Valid != (tt.wantCode == "") {
+				t.Errorf("valid=%v code=%s (%s), want code %q", result.Valid, result.ErrorCode, result.ErrorMessage, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestVerifyLocal_CheckPaymentConsistency(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	issueWith := func(ext map[string]any) string {
+		t.Helper()
+		issued, err := Issue(IssueOptions{
+			Iss:        "https://example.com",
+			Kind:       KindEvidence,
+			Type:       "org.peacprotocol/payment",
+			SigningKey: key,
+			Extensions: ext,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return issued.JWS
+	}
+	commerce := func(fields map[string]any) map[string]any {
+		c := map[string]any{"payment_rail": "x402", "amount_minor": "500", "currency": "USD"}
+		for k, v := range fields {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return map[string]any{CommerceExtensionKey: c}
+	}
+
+	tests := []struct {
+		name    string
+		jws     string
+		wantMsg string
+	}{
+		{"consistent", issueWith(commerce(nil)), ""},
+		{"no commerce extension", issueWith(nil), "commerce extension is required"},
+		{"missing rail", issueWith(commerce(map[string]any{"payment_rail": nil})), "commerce.payment_rail"},
+		{"missing currency", issueWith(commerce(map[string]any{"currency": nil})), "commerce.currency"},
+		{"refund", issueWith(commerce(map[string]any{"amount_minor": "-50", "event": "refund"})), ""},
+		{"arbitrary precision", issueWith(commerce(map[string]any{"amount_minor": "123456789012345678901234567890"})), ""},
+		{"decimal amount", issueWith(commerce(map[string]any{"amount_minor": "5.00"})), "commerce.amount_minor"},
+		{"bare sign", issueWith(commerce(map[string]any{"amount_minor": "-"})), "commerce.amount_minor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := VerifyLocal(tt.jws, VerifyLocalOptions{
+				PublicKey:               key.PublicKey(),
+				CheckPaymentConsistency: true,
+			})
+			if tt.wantMsg == "" {
+				if !result.Valid {
+					t.Errorf("expected valid, got %s: %s", result.ErrorCode, result.ErrorMessage)
+				}
+				return
+			}
+			if result.Valid || result.ErrorCode != "E_INVALID_FORMAT" || !strings.Contains(result.ErrorMessage, tt.wantMsg) {
+				t.Errorf("valid=%v code=%s msg=%q, want E_INVALID_FORMAT containing %q", result.Valid, result.ErrorCode, result.ErrorMessage, tt.wantMsg)
+			}
+		})
+	}
+
+	// Off by default
+	if result := VerifyLocal(issueWith(nil), VerifyLocalOptions{PublicKey: key.PublicKey()}); !result.Valid {
+		t.Errorf("unchecked record without commerce: %s", result.ErrorMessage)
+	}
+}
+
+func TestVerifyLocal_UnsecuredJWS(t *testing.T) {
+	key, _ := jws.GenerateSigningKey("key-1")
+	signed, err := key.SignWithType([]byte(`{"iss":"https://example.com"}`), InteractionRecordTyp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(signed, ".")
+	none := jws.Encode([]byte(`{"alg":"none","typ":"` + InteractionRecordTyp + `","kid":"key-1"}`))
+
+	tests := []struct {
+		name     string
+		receipt  string
+		wantCode string
+		wantMsg  string
+	}{
+		{"alg none", none + "." + parts[1] + ".", "E_INVALID_FORMAT", jws.ErrAlgNone.Error()},
+		{"signature stripped", parts[0] + "." + parts[1] + ".", "E_INVALID_SIGNATURE", jws.ErrEmptySignature.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := VerifyLocal(tt.receipt, VerifyLocalOptions{PublicKey: key.PublicKey()})
+			if r.Valid || r.ErrorCode != tt.wantCode || !strings.Contains(r.ErrorMessage, tt.wantMsg) {
+				t.Errorf("valid=%v code=%s msg=%q, want %s containing %q", r.Valid, r.ErrorCode, r.ErrorMessage, tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestValidateClaims(t *testing.T) {
+	now := time.Now().Unix()
+	valid := func() *InteractionRecordClaims {
+		return &InteractionRecordClaims{
+			PeacVersion: PeacVersion,
+			Kind:        KindEvidence,
+			Type:        "org.peacprotocol/test",
+			Iss:         "https://example.com",
+			Iat:         now,
+			Jti:         "rec-1",
+		}
+	}
+
+	if err := ValidateClaims(valid(), VerifyLocalOptions{Issuer: "https://example.com"}); err != nil {
+		t.Fatalf("expected valid claims, got %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		mutate    func(*InteractionRecordClaims)
+		opts      VerifyLocalOptions
+		wantCode  ErrorCode
+		wantCheck string
+	}{
+		{"wrong version", func(c *InteractionRecordClaims) { c.PeacVersion = "0.1" }, VerifyLocalOptions{}, "E_UNSUPPORTED_WIRE_VERSION", "peac_version"},
+		{"expired", func(c *InteractionRecordClaims) { c.Exp = now - 3600 }, VerifyLocalOptions{}, ErrExpired, "exp"},
+		{"issuer mismatch", func(*InteractionRecordClaims) {}, VerifyLocalOptions{Issuer: "https://other.example"}, ErrInvalidIssuer, "issuer"},
+		{"currency mismatch", func(*InteractionRecordClaims) {}, VerifyLocalOptions{ExpectedCurrency: "USD"}, ErrCurrencyMismatch, "currency"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid()
+			tt.mutate(claims)
+			err := ValidateClaims(claims, tt.opts)
+			var perr *PEACError
+			if !errors.As(err, &perr) {
+				t.Fatalf("err = %v, want *PEACError", err)
+			}
+			if perr.Code != tt.wantCode || perr.Details["check"] != tt.wantCheck {
+				t.Errorf("code=%s check=%v, want %s / %s", perr.Code, perr.Details["check"], tt.wantCode, tt.wantCheck)
+			}
+		})
+	}
+
+	if err := ValidateClaims(nil, VerifyLocalOptions{}); err == nil {
+		t.Error("expected error for nil claims")
+	}
+}
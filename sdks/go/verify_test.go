@@ -0,0 +1,33 @@
+package peac
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestStatusForError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, http.StatusOK},
+		{"invalid signature", NewPEACError(ErrInvalidSignature, "bad"), http.StatusBadRequest},
+		{"expired", NewPEACError(ErrExpired, "old"), http.StatusUnauthorized},
+		{"wrapped peac error", fmt.Errorf("verify: %w", NewPEACError(ErrIdentityMissing, "missing")), http.StatusUnauthorized},
+		{"jwks unavailable", NewPEACError(ErrJWKSFetchFailed, "down"), http.StatusServiceUnavailable},
+		{"issue validation", &IssueError{Code: ErrCodeMissingIssuer, Message: "iss required"}, http.StatusBadRequest},
+		{"issue signing", &IssueError{Code: ErrCodeSignFailed, Message: "sign"}, http.StatusInternalServerError},
+		{"sentinel", ErrInvalidKind, http.StatusBadRequest},
+		{"unknown", errors.New("boom"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatusForError(tt.err); got != tt.want {
+				t.Errorf("StatusForError() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}